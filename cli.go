@@ -0,0 +1,625 @@
+package main
+
+import (
+	"context"
+	"covid-stats-cli/internal/barchart"
+	"covid-stats-cli/internal/coviddata"
+	"covid-stats-cli/internal/export"
+	"covid-stats-cli/internal/logging"
+	"covid-stats-cli/internal/report"
+	"covid-stats-cli/internal/rest"
+	"covid-stats-cli/internal/terminal"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	exitOk    = 0
+	exitError = 1
+	exitUsage = 2
+	// exitNoData is there being no figures for what was asked for, from the api or, offline, the cache
+	exitNoData = 3
+	// exitUnavailable is not getting an answer from the api, e.g. it's down, busy or too slow
+	exitUnavailable = 4
+)
+
+// config holds the flags that apply to both the interactive menu and the commands
+type config struct {
+	refresh        bool
+	offline        bool
+	populationFile string
+	width          int
+	style          string
+	gaps           string
+	ascii          bool
+	colour         string
+	timeout        time.Duration
+	verbose        bool
+	quiet          bool
+}
+
+// defaultTimeout is how long to wait for each of the api's responses when not told otherwise
+const defaultTimeout = 30 * time.Second
+
+func (c *config) register(flags *flag.FlagSet) {
+	flags.BoolVar(&c.refresh, "refresh", c.refresh, "ignore any cached data and fetch it again")
+	flags.BoolVar(&c.offline, "offline", c.offline, "only use cached data, never call the api")
+	flags.StringVar(&c.populationFile, "population-file", c.populationFile,
+		"a csv of area code and population pairs to use for the per 100k rates")
+	flags.IntVar(&c.width, "width", c.width, "how many characters wide the charts can be (default the terminal width)")
+	flags.StringVar(&c.style, "style", c.style, "horizontal (default), blocks, vertical or sparkline")
+	flags.StringVar(&c.gaps, "gaps", c.gaps, "mark (default), carry, interpolate or drop the days the api is missing")
+	flags.BoolVar(&c.ascii, "ascii", c.ascii, "never draw with unicode, even if the locale supports it")
+	flags.StringVar(&c.colour, "color", c.colour, "auto (default), always or never colour the charts")
+	flags.DurationVar(&c.timeout, "timeout", c.timeout, "how long to wait for each response from the api, 0 for no limit")
+	flags.BoolVar(&c.verbose, "verbose", c.verbose, "log each request to the api on stderr")
+	flags.BoolVar(&c.quiet, "quiet", c.quiet, "only write errors on stderr, not warnings about the data")
+}
+
+func (c config) validate() error {
+	if c.refresh && c.offline {
+		return errors.New("--refresh and --offline can't be used together")
+	}
+	if c.width < 0 {
+		return errors.New("--width can't be negative")
+	}
+	if c.verbose && c.quiet {
+		return errors.New("--verbose and --quiet can't be used together")
+	}
+	if c.timeout < 0 {
+		return errors.New("--timeout can't be negative")
+	}
+	if c.style != "" {
+		if _, err := coviddata.ParseChartStyle(c.style); err != nil {
+			return err
+		}
+	}
+	if c.gaps != "" {
+		if _, err := coviddata.ParseGapPolicy(c.gaps); err != nil {
+			return err
+		}
+	}
+	if _, err := terminal.ParseColourMode(c.colour); err != nil {
+		return err
+	}
+	return nil
+}
+
+// colourMode is the mode asked for with --color, which validate has already checked
+func (c config) colourMode() terminal.ColourMode {
+	mode, err := terminal.ParseColourMode(c.colour)
+	if err != nil {
+		return terminal.ColourAuto
+	}
+	return mode
+}
+
+// chartStyle is the style asked for with --style, which validate has already checked
+func (c config) chartStyle() coviddata.ChartStyle {
+	style, err := coviddata.ParseChartStyle(c.style)
+	if err != nil {
+		return coviddata.Horizontal
+	}
+	return style
+}
+
+// gapPolicy is the policy asked for with --gaps, which validate has already checked
+func (c config) gapPolicy() coviddata.GapPolicy {
+	policy, err := coviddata.ParseGapPolicy(c.gaps)
+	if err != nil {
+		return coviddata.GapMark
+	}
+	return policy
+}
+
+// populations are the built in estimates plus anything in the population file
+func (c config) populations() (coviddata.Populations, error) {
+	populations := coviddata.DefaultPopulations()
+	if c.populationFile == "" {
+		return populations, nil
+	}
+
+	file, err := os.Open(c.populationFile)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	if err := populations.Override(file); err != nil {
+		return nil, fmt.Errorf("couldn't read the population file %s: %w", c.populationFile, err)
+	}
+	return populations, nil
+}
+
+// logger writes to w, usually stderr, everything from debug when verbose, only errors when quiet
+// and from info otherwise
+func (c config) logger(w io.Writer) *logging.Logger {
+	if c.verbose {
+		return logging.New(w, logging.Debug)
+	}
+	if c.quiet {
+		return logging.New(w, logging.Error)
+	}
+	return logging.New(w, logging.Info)
+}
+
+func (c config) cacheMode() rest.CacheMode {
+	if c.offline {
+		return rest.Offline
+	}
+	if c.refresh {
+		return rest.RefreshCache
+	}
+	return rest.UseCache
+}
+
+// runCommand handles the non-interactive mode, e.g. `covid-stats-cli deaths --weeks 6`,
+// and returns the status code the process should exit with
+func runCommand(ctx context.Context, args []string, cfg config, newClient clientFactory, stdout io.Writer,
+	stderr io.Writer) int {
+	command := args[0]
+
+	switch command {
+	case "help", "-h", "-help", "--help":
+		printUsage(stdout)
+		return exitOk
+	case "report":
+		return runReport(ctx, args[1:], cfg, newClient, stdout, stderr)
+	}
+
+	metric, err := coviddata.MetricByName(command)
+	if err != nil {
+		fmt.Fprintf(stderr, "unknown command '%s'\n\n", command)
+		printUsage(stderr)
+		return exitUsage
+	}
+
+	flags := flag.NewFlagSet(command, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	var figures queryFlags
+	figures.register(flags)
+	areaType := flags.String("area-type", string(coviddata.England.Type), "the type of area, e.g. nation, region, utla")
+	areaName := flags.String("area-name", coviddata.England.Name, "the name of the area, e.g. Scotland, London")
+	compare := flags.String("compare", "", "areas to chart side by side, e.g. region:London,utla:Manchester")
+	output := flags.String("output", string(export.Chart), "chart, or json, csv or tsv for the figures behind it")
+	exportTo := flags.String("export", "", "save the chart as an image too, e.g. chart.svg or chart.png")
+	cfg.register(flags)
+
+	if err := flags.Parse(args[1:]); err != nil {
+		if err == flag.ErrHelp {
+			return exitOk
+		}
+		return exitUsage
+	}
+
+	if flags.NArg() > 0 {
+		fmt.Fprintf(stderr, "unexpected arguments: %v\n", flags.Args())
+		return exitUsage
+	}
+
+	if err := cfg.validate(); err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitUsage
+	}
+
+	// the chart, its export, the figures behind it and the summary are all the same query rendered
+	// differently, from one fetch
+	query, err := figures.query(flags, []coviddata.Metric{metric}, time.Now())
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitUsage
+	}
+
+	area, err := coviddata.NewArea(*areaType, *areaName)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitUsage
+	}
+
+	format, err := export.ParseFormat(*output)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitUsage
+	}
+
+	if *exportTo != "" {
+		if err := checkImagePath(*exportTo); err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitUsage
+		}
+		if *compare != "" {
+			fmt.Fprintln(stderr, "--export can only save a single area's chart, not a --compare")
+			return exitUsage
+		}
+		if format != export.Chart {
+			fmt.Fprintf(stderr, "--export saves the chart, so can't be used with --output %s\n", format)
+			return exitUsage
+		}
+	}
+
+	// the warnings about the data are written once everything else has been
+	log := cfg.logger(stderr)
+	defer log.Flush()
+
+	handler, err := newHandler(area, cfg, newClient(cfg, log), log)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitUsage
+	}
+
+	query.Metric, query.Area = metric, area
+	if *compare != "" {
+		query.Compare, err = parseAreas(*compare)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitUsage
+		}
+	}
+
+	if format != export.Chart {
+		query.Render = []coviddata.Renderer{coviddata.RenderSeries}
+		result, err := handler.Run(ctx, query)
+		if err != nil {
+			return fetchFailed(stderr, "the "+command+" stats", err)
+		}
+
+		if err := export.Write(stdout, format, result.Series); err != nil {
+			fmt.Fprintf(stderr, "Error writing the %s stats: %+v\n", command, err)
+			return exitError
+		}
+		return exitOk
+	}
+
+	query.Render = []coviddata.Renderer{coviddata.RenderChart}
+	if *exportTo != "" {
+		query.Render = append(query.Render, coviddata.RenderBarChart)
+	}
+	if len(query.Compare) == 0 {
+		query.Render = append(query.Render, coviddata.RenderSummary)
+	}
+
+	result, err := handler.Run(ctx, query)
+	if err != nil {
+		return fetchFailed(stderr, "the "+command+" stats", err)
+	}
+
+	fmt.Fprintln(stdout, result.Chart)
+
+	if *exportTo != "" {
+		if err := saveImage(*exportTo, result.BarChart); err != nil {
+			fmt.Fprintf(stderr, "Error saving the chart to %s: %+v\n", *exportTo, err)
+			return exitError
+		}
+		log.Infof("Saved the chart to %s", *exportTo)
+	}
+
+	if len(query.Compare) == 0 {
+		fmt.Fprintln(stdout, result.Summary)
+	}
+
+	return exitOk
+}
+
+// fetchFailed explains why what couldn't be fetched on stderr, and returns the exit code for it
+func fetchFailed(stderr io.Writer, what string, err error) int {
+	message, code := explain(err)
+	fmt.Fprintf(stderr, "Couldn't fetch %s: %s\n", what, message)
+	return code
+}
+
+// explain turns an error fetching the figures into something the user can act on, and the status
+// code to exit with
+func explain(err error) (string, int) {
+	var statusErr *coviddata.StatusError
+	var decodeErr *coviddata.DecodeError
+	var queryErr *coviddata.QueryError
+	var netErr net.Error
+
+	switch {
+	case errors.As(err, &queryErr):
+		return queryErr.Error(), exitUsage
+	case errors.Is(err, context.Canceled):
+		return "it was cancelled", exitError
+	case errors.Is(err, rest.ErrNotCached):
+		return "there's no cached copy of it to use offline, run it once without --offline first", exitNoData
+	case errors.Is(err, coviddata.ErrEmptyResponse):
+		return err.Error(), exitNoData
+	case errors.As(err, &statusErr) && statusErr.Temporary():
+		return fmt.Sprintf("the dashboard is busy or having problems (%d %s), try again in a few minutes",
+			statusErr.StatusCode, http.StatusText(statusErr.StatusCode)), exitUnavailable
+	case errors.As(err, &statusErr):
+		return fmt.Sprintf("the dashboard turned the request down (%d %s)",
+			statusErr.StatusCode, http.StatusText(statusErr.StatusCode)), exitError
+	case errors.As(err, &decodeErr), errors.Is(err, coviddata.ErrMissingDate):
+		return "the dashboard sent back something other than the figures, its api may have changed", exitError
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return "the dashboard took too long to answer, try again or allow longer with --timeout", exitUnavailable
+	case errors.As(err, &netErr):
+		return fmt.Sprintf("couldn't reach the dashboard, are you online? (%v)", err), exitUnavailable
+	}
+
+	return err.Error(), exitError
+}
+
+// runReport handles `covid-stats-cli report`, which writes a document with a section for each metric
+// in each area
+func runReport(ctx context.Context, args []string, cfg config, newClient clientFactory, stdout io.Writer,
+	stderr io.Writer) int {
+	flags := flag.NewFlagSet("report", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	formatName := flags.String("format", string(report.Markdown), "markdown or html")
+	metricNames := flags.String("metrics", "cases,deaths", "the metrics to report on, e.g. cases,admissions")
+	areaList := flags.String("areas", "nation:England", "the areas to report on, e.g. nation:England,region:London")
+	var figures queryFlags
+	figures.register(flags)
+	cfg.register(flags)
+
+	if err := flags.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return exitOk
+		}
+		return exitUsage
+	}
+
+	if flags.NArg() > 0 {
+		fmt.Fprintf(stderr, "unexpected arguments: %v\n", flags.Args())
+		return exitUsage
+	}
+
+	if err := cfg.validate(); err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitUsage
+	}
+
+	format, err := report.ParseFormat(*formatName)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitUsage
+	}
+
+	var metrics []coviddata.Metric
+	for _, name := range strings.Split(*metricNames, ",") {
+		metric, err := coviddata.MetricByName(name)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitUsage
+		}
+		metrics = append(metrics, metric)
+	}
+
+	areas, err := parseAreas(*areaList)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitUsage
+	}
+
+	query, err := figures.query(flags, metrics, time.Now())
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitUsage
+	}
+
+	// the warnings about the data are written once everything else has been
+	log := cfg.logger(stderr)
+	defer log.Flush()
+
+	handler, err := newHandler(areas[0], cfg, newClient(cfg, log), log)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitUsage
+	}
+	// a document isn't a terminal, so its charts are a fixed width without escape codes
+	handler.SetColour(false)
+	if cfg.width == 0 {
+		handler.SetWidth(terminal.DefaultWidth)
+	}
+
+	r, err := report.Build(ctx, metrics, areas, query, handler, covidApiUrl(), time.Now())
+	if err != nil {
+		return fetchFailed(stderr, "the report", err)
+	}
+
+	if err := r.Write(stdout, format); err != nil {
+		fmt.Fprintf(stderr, "Error writing the report: %+v\n", err)
+		return exitError
+	}
+
+	return exitOk
+}
+
+// queryFlags holds the flags that say which figures to chart, shared by the metric commands and the
+// report
+type queryFlags struct {
+	weeks     int
+	since     string
+	rolling   int
+	per100k   bool
+	dateBasis string
+}
+
+func (q *queryFlags) register(flags *flag.FlagSet) {
+	flags.IntVar(&q.weeks, "weeks", 1, "number of previous weeks to chart")
+	flags.StringVar(&q.since, "since", "", "chart every day since this date (YYYY-MM-DD)")
+	flags.IntVar(&q.rolling, "rolling", 0, "chart an N-day rolling average instead of the daily figures")
+	flags.BoolVar(&q.per100k, "per-100k", false, "chart the figures per 100,000 people living in the area")
+	flags.StringVar(&q.dateBasis, "date-basis", string(coviddata.PublishDate),
+		"publish, or event (specimen for cases, death for deaths) to chart by when it happened")
+}
+
+// query checks the parsed flags and returns the query they ask for, without its metric or areas.
+// Every one of metrics has to accept the date basis, as they're all charted by it
+func (q queryFlags) query(flags *flag.FlagSet, metrics []coviddata.Metric, now time.Time) (coviddata.Query, error) {
+	previousDays, err := previousDaysFromFlags(q.weeks, isSet(flags, "weeks"), q.since, now)
+	if err != nil {
+		return coviddata.Query{}, err
+	}
+
+	if q.rolling < 0 {
+		return coviddata.Query{}, fmt.Errorf("--rolling must be positive, got %d", q.rolling)
+	}
+
+	var basis coviddata.DateBasis
+	for _, metric := range metrics {
+		if basis, err = coviddata.ParseDateBasis(q.dateBasis, metric); err != nil {
+			return coviddata.Query{}, err
+		}
+	}
+
+	return coviddata.Query{PreviousDays: previousDays, DateBasis: basis, RollingWindow: q.rolling,
+		Per100k: q.per100k}, nil
+}
+
+// previousDaysFromFlags is how many days --weeks or --since ask to chart. weeksSet is whether --weeks
+// was given rather than left at its default of 1
+func previousDaysFromFlags(weeks int, weeksSet bool, since string, now time.Time) (int, error) {
+	if weeksSet && since != "" {
+		return 0, errors.New("--weeks and --since can't be used together")
+	}
+
+	if since != "" {
+		from, err := time.Parse("2006-01-02", since)
+		if err != nil {
+			return 0, fmt.Errorf("--since '%s' should be a date formatted as YYYY-MM-DD", since)
+		}
+		if from.After(now) {
+			return 0, fmt.Errorf("--since '%s' is in the future", since)
+		}
+		return int(now.Sub(from).Hours() / 24), nil
+	}
+
+	if weeks < 1 {
+		return 0, fmt.Errorf("--weeks must be at least 1, got %d", weeks)
+	}
+
+	return weeks * 7, nil
+}
+
+// isSet reports whether the flag was given on the command line, rather than left at its default
+func isSet(flags *flag.FlagSet, name string) bool {
+	set := false
+	flags.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+// parseAreas reads a comma separated list of areas, each given as type:name
+func parseAreas(list string) ([]coviddata.Area, error) {
+	var areas []coviddata.Area
+	for _, item := range strings.Split(list, ",") {
+		parts := strings.SplitN(item, ":", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("'%s' should be given as type:name, e.g. region:London", item)
+		}
+
+		area, err := coviddata.NewArea(parts[0], parts[1])
+		if err != nil {
+			return nil, err
+		}
+		areas = append(areas, area)
+	}
+
+	return areas, nil
+}
+
+// checkImagePath makes sure the file is one saveImage knows how to write
+func checkImagePath(path string) error {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".svg", ".png":
+		return nil
+	}
+	return fmt.Errorf("--export '%s' should end in .svg or .png", path)
+}
+
+// saveImage writes the chart to the file as an SVG or PNG, depending on its extension
+func saveImage(path string, chart barchart.BarChart) (err error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	if strings.ToLower(filepath.Ext(path)) == ".png" {
+		return chart.WritePNG(file)
+	}
+	return chart.WriteSVG(file)
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "Usage:")
+	fmt.Fprintln(w, "  covid-stats-cli [flags]         start the interactive menu")
+	fmt.Fprintln(w, "  covid-stats-cli METRIC [flags]  chart a metric, one of:")
+	fmt.Fprintln(w)
+	for _, metric := range coviddata.Metrics {
+		fmt.Fprintf(w, "    %-13s %s\n", metric.Name, metric.Title)
+	}
+	fmt.Fprintln(w, "  covid-stats-cli report [flags]  write a markdown or html report, see below")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Flags:")
+	fmt.Fprintln(w, "  --weeks N           chart the previous N weeks (default 1)")
+	fmt.Fprintln(w, "  --since YYYY-MM-DD  chart every day since the given date")
+	fmt.Fprintln(w, "  --area-type TYPE    overview, nation, region, nhsRegion, utla or ltla (default nation)")
+	fmt.Fprintln(w, "  --area-name NAME    the area to chart, e.g. Scotland or London (default England)")
+	fmt.Fprintln(w, "  --rolling N         chart an N-day rolling average, e.g. 7, instead of the daily figures")
+	fmt.Fprintln(w, "  --per-100k          chart the figures per 100,000 people living in the area")
+	fmt.Fprintln(w, "  --date-basis BASIS  publish (default), or event to chart cases by specimen date and deaths")
+	fmt.Fprintln(w, "                      by date of death. specimen and death can be used for cases and deaths.")
+	fmt.Fprintln(w, "                      Metrics only published by publish date are still charted by it.")
+	fmt.Fprintln(w, "                      A report on several metrics takes event, as specimen and death")
+	fmt.Fprintln(w, "                      are each only one metric's")
+	fmt.Fprintln(w, "  --compare AREAS     chart several areas side by side, e.g. region:London,utla:Manchester")
+	fmt.Fprintln(w, "  --output FORMAT     chart (default), or json, csv or tsv to print the figures behind the chart,")
+	fmt.Fprintln(w, "                      with the rolling averages and rates per 100k when they're asked for")
+	fmt.Fprintln(w, "  --export FILE       save the chart as an image as well, an SVG or PNG depending on whether")
+	fmt.Fprintln(w, "                      FILE ends in .svg or .png")
+	fmt.Fprintln(w, "  --refresh           ignore any cached data and fetch it again")
+	fmt.Fprintln(w, "  --offline           only use cached data, never call the api")
+	fmt.Fprintln(w, "  --population-file F a csv of area code,population pairs adding to or replacing the")
+	fmt.Fprintln(w, "                      built in population estimates")
+	fmt.Fprintln(w, "  --width N           fit the charts into N columns (default the terminal width)")
+	fmt.Fprintln(w, "  --style STYLE       how to draw the charts, one of:")
+	fmt.Fprintln(w, "                        horizontal  a row of * per day (default)")
+	fmt.Fprintln(w, "                        blocks      a row of unicode blocks per day, showing eighths")
+	fmt.Fprintln(w, "                        vertical    a column per day, averaging days that don't fit")
+	fmt.Fprintln(w, "                        sparkline   one line per area, handy with --compare")
+	fmt.Fprintln(w, "                      --compare draws rows unless the style is sparkline")
+	fmt.Fprintln(w, "  --gaps POLICY       what to chart for a day the api is missing a figure for, one of:")
+	fmt.Fprintln(w, "                        mark         leave it empty, marked with a ? (default)")
+	fmt.Fprintln(w, "                        carry        carry forward the figure before, marked as an estimate")
+	fmt.Fprintln(w, "                        interpolate  a straight line between the figures either side,")
+	fmt.Fprintln(w, "                                     marked as an estimate")
+	fmt.Fprintln(w, "                        drop         leave the day out")
+	fmt.Fprintln(w, "  --ascii             never draw with unicode, which is only used when the locale is UTF-8")
+	fmt.Fprintln(w, "  --color WHEN        auto (default) colours the charts when writing to a terminal and")
+	fmt.Fprintln(w, "                      NO_COLOR isn't set, always or never override that")
+	fmt.Fprintln(w, "  --timeout DURATION  give up on a response from the api after this long, e.g. 1m (default 30s),")
+	fmt.Fprintln(w, "                      0 to wait as long as it takes")
+	fmt.Fprintln(w, "  --verbose           log each request to the api on stderr, including the retries when it's busy")
+	fmt.Fprintln(w, "  --quiet             only write errors on stderr, without the warnings about missing figures")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Report flags, as well as --weeks, --since, --rolling, --per-100k and --date-basis:")
+	fmt.Fprintln(w, "  --format FORMAT     markdown (default) with text charts, or html with SVG charts")
+	fmt.Fprintln(w, "  --metrics METRICS   the metrics to report on (default cases,deaths)")
+	fmt.Fprintln(w, "  --areas AREAS       the areas to report on (default nation:England), e.g. region:London")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Exit codes:")
+	fmt.Fprintln(w, "  0  the chart, figures or report were written")
+	fmt.Fprintln(w, "  1  something went wrong")
+	fmt.Fprintln(w, "  2  the command or its flags weren't right")
+	fmt.Fprintln(w, "  3  there's no data for what was asked for, or no cached copy of it with --offline")
+	fmt.Fprintln(w, "  4  the dashboard couldn't be reached, was too slow or is having problems")
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"covid-stats-cli/internal/coviddata"
+	"covid-stats-cli/internal/logging"
+	"covid-stats-cli/internal/rest"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// timeoutError is how the http client says it gave up waiting
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestExplain_ExitCodes(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected int
+	}{
+		{"offline without a cache", rest.ErrNotCached, exitNoData},
+		{"no data", fmt.Errorf("%w for England", coviddata.ErrEmptyResponse), exitNoData},
+		{"rate limited", &coviddata.StatusError{StatusCode: 429}, exitUnavailable},
+		{"api error", &coviddata.StatusError{StatusCode: 503}, exitUnavailable},
+		{"bad request", &coviddata.StatusError{StatusCode: 400}, exitError},
+		{"bad json", &coviddata.DecodeError{Err: errors.New("unexpected end of JSON input")}, exitError},
+		{"missing date", coviddata.ErrMissingDate, exitError},
+		{"timeout", &url.Error{Op: "Get", URL: "http://www.amireallyreal.com", Err: timeoutError{}}, exitUnavailable},
+		{"deadline", context.DeadlineExceeded, exitUnavailable},
+		{"offline", &url.Error{Op: "Get", URL: "http://www.amireallyreal.com", Err: &net.DNSError{Err: "no such host"}}, exitUnavailable},
+		{"anything else", errors.New("our data centre went bye bye"), exitError},
+	}
+
+	for _, test := range tests {
+		// the errors reach the cli wrapped, e.g. by the report
+		wrapped := fmt.Errorf("couldn't report the cases for England: %w", test.err)
+
+		if _, code := explain(wrapped); code != test.expected {
+			t.Errorf("Expected %s to exit with %d, got %d", test.name, test.expected, code)
+		}
+	}
+}
+
+func TestPreviousDaysFromFlags(t *testing.T) {
+	now := time.Date(2021, 3, 10, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		weeks    int
+		weeksSet bool
+		since    string
+		expected int
+		fails    bool
+	}{
+		{name: "default", weeks: 1, expected: 7},
+		{name: "weeks", weeks: 6, weeksSet: true, expected: 42},
+		{name: "zero weeks", weeks: 0, weeksSet: true, fails: true},
+		{name: "negative weeks", weeks: -2, weeksSet: true, fails: true},
+		{name: "since", weeks: 1, since: "2021-03-01", expected: 9},
+		{name: "since today", weeks: 1, since: "2021-03-10", expected: 0},
+		{name: "since the future", weeks: 1, since: "2021-03-11", fails: true},
+		{name: "since isn't a date", weeks: 1, since: "01/03/2021", fails: true},
+		{name: "weeks and since", weeks: 2, weeksSet: true, since: "2021-03-01", fails: true},
+	}
+
+	for _, test := range tests {
+		days, err := previousDaysFromFlags(test.weeks, test.weeksSet, test.since, now)
+
+		if test.fails && err == nil {
+			t.Errorf("%s: expected an error, got %d days", test.name, days)
+		}
+		if !test.fails && (err != nil || days != test.expected) {
+			t.Errorf("%s: expected %d days, got %d and err %v", test.name, test.expected, days, err)
+		}
+	}
+}
+
+func runCommandWith(args []string, client *fakeClient) (string, string, int) {
+	var stdout, stderr bytes.Buffer
+	newClient := func(config, *logging.Logger) rest.Client {
+		return client
+	}
+
+	code := runCommand(context.Background(), args, config{}, newClient, &stdout, &stderr)
+	return stdout.String(), stderr.String(), code
+}
+
+func TestRunCommand_ChartsAndSummarises(t *testing.T) {
+	client := &fakeClient{}
+	stdout, stderr, code := runCommandWith([]string{"cases", "--weeks", "1", "--width", "80"}, client)
+
+	if code != exitOk {
+		t.Fatalf("Expected to exit with %d, got %d and %s", exitOk, code, stderr)
+	}
+	if !strings.Contains(stdout, "New cases in England") || !strings.Contains(stdout, "New cases in England summary") {
+		t.Errorf("Expected the chart and its summary, got %s", stdout)
+	}
+	if client.calls != 1 {
+		t.Errorf("Expected the chart and its summary to come from one request, got %d", client.calls)
+	}
+}
+
+func TestRunCommand_RejectsBadFlags(t *testing.T) {
+	for _, args := range [][]string{
+		{"cases", "--weeks", "0"},
+		{"cases", "--weeks", "2", "--since", "2021-01-01"},
+		{"cases", "--since", "2999-01-01"},
+		{"cases", "--date-basis", "death"},
+		{"positivity", "--per-100k"},
+		{"cases", "extra"},
+		{"cured"},
+		{"report", "--rolling", "-7"},
+		{"report", "--metrics", "cases,deaths", "--date-basis", "specimen"},
+	} {
+		client := &fakeClient{}
+		_, _, code := runCommandWith(args, client)
+
+		if code != exitUsage || client.calls != 0 {
+			t.Errorf("Expected %v to exit with %d without fetching, got %d after %d requests",
+				args, exitUsage, code, client.calls)
+		}
+	}
+}
+
+func TestRunCommand_ReportChartsEveryMetricByEventDate(t *testing.T) {
+	client := &fakeClient{}
+	stdout, stderr, code := runCommandWith([]string{"report", "--metrics", "cases,deaths", "--date-basis", "event"}, client)
+
+	if code != exitOk {
+		t.Fatalf("Expected to exit with %d, got %d and %s", exitOk, code, stderr)
+	}
+	if !strings.Contains(stdout, "New cases by specimen date") || !strings.Contains(stdout, "New deaths by date of death") {
+		t.Errorf("Expected cases by specimen date and deaths by date of death, got %s", stdout)
+	}
+}
+
+func TestRunCommand_ExitsWithTheCodeForTheFailure(t *testing.T) {
+	_, stderr, code := runCommandWith([]string{"deaths"}, &fakeClient{status: 503})
+
+	if code != exitUnavailable || !strings.Contains(stderr, "the dashboard is busy") {
+		t.Errorf("Expected to exit with %d explaining the dashboard's busy, got %d and %s", exitUnavailable, code, stderr)
+	}
+}
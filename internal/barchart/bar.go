@@ -4,36 +4,121 @@ import "strings"
 
 type Bar struct {
 	label string
-	count int
+	value float64
+	// an incomplete bar's value is still being reported and is likely to go up
+	incomplete bool
+	// an estimated bar's value fills a gap in the figures, e.g. by interpolating
+	estimated bool
+	// a gap has no value at all, its figure is missing
+	gap bool
 }
 
 func (b Bar) Label() string {
 	return b.label
 }
 
-func (b Bar) Count() int {
-	return b.count
+func (b Bar) Value() float64 {
+	return b.value
 }
 
-func NewBar(label string, count int) Bar {
-	maxSize := 5
+func (b Bar) IsIncomplete() bool {
+	return b.incomplete
+}
+
+// MarkIncomplete returns a copy of the bar that's drawn differently, so the chart doesn't suggest
+// a drop that's really just late reporting
+func (b Bar) MarkIncomplete() Bar {
+	b.incomplete = true
+	return b
+}
+
+func (b Bar) IsEstimated() bool {
+	return b.estimated
+}
+
+func (b Bar) IsGap() bool {
+	return b.gap
+}
+
+// MarkEstimated returns a copy of the bar that's drawn as an estimate rather than a real figure
+func (b Bar) MarkEstimated() Bar {
+	b.estimated = true
+	return b
+}
+
+// MarkGap returns a copy of the bar with no value, for a day that's missing its figure
+func (b Bar) MarkGap() Bar {
+	b.gap = true
+	b.value = 0
+	return b
+}
+
+func NewBar(label string, value float64) Bar {
+	return Bar{label: label, value: value}
+}
+
+func highestValue(bars []Bar) float64 {
+	highest := 0.0
+	for _, bar := range bars {
+		if bar.value > highest {
+			highest = bar.value
+		}
+	}
+	return highest
+}
+
+// valueLabel is the bar's value as it's shown beside it, with a ~ before an estimate and a ? for a gap
+func valueLabel(bar Bar, format Formatter) string {
+	if bar.gap {
+		return gapLabel
+	}
+	if bar.estimated {
+		return estimatedMarker + format(bar.value)
+	}
+	return format(bar.value)
+}
+
+// widestValue is the length of the longest value label, for lining up the bars
+func widestValue(bars []Bar, format Formatter) int {
+	widest := 0
+	for _, bar := range bars {
+		if len(valueLabel(bar, format)) > widest {
+			widest = len(valueLabel(bar, format))
+		}
+	}
+	return widest
+}
 
-	// trim the label if it's > 5 chars long
-	if len(label) > maxSize {
-		return Bar{label[:maxSize], count}
+// scaledLength is how many characters long the bar is drawn. Negative values have no length
+func scaledLength(value float64, scaleFactor float64) int {
+	if value <= 0 {
+		return 0
 	}
+	// allow for float rounding, e.g. 12.5 * (65 / 12.5) coming out as 64.99999
+	return int(value*scaleFactor + 1e-9)
+}
 
-	// otherwise add padding so the label is 5 chars long
-	leftPadding, rightPadding := getPadding(label, maxSize)
-	return Bar{strings.Repeat(" ", leftPadding) + label + strings.Repeat(" ", rightPadding), count}
+func longestLabel(bars []Bar) int {
+	longest := 0
+	for _, bar := range bars {
+		if len(bar.label) > longest {
+			longest = len(bar.label)
+		}
+	}
+	return longest
+}
+
+// padLabel centres the label in a space width characters wide
+func padLabel(label string, width int) string {
+	leftPadding, rightPadding := getPadding(label, width)
+	return strings.Repeat(" ", leftPadding) + label + strings.Repeat(" ", rightPadding)
 }
 
 func getPadding(label string, maxSize int) (left int, right int) {
 	totalPadding := maxSize - len(label)
-	left, right = totalPadding / 2, totalPadding / 2
-	if totalPadding % 2 != 0 {
+	left, right = totalPadding/2, totalPadding/2
+	if totalPadding%2 != 0 {
 		right = right + 1
 	}
 	return left, right
 }
-
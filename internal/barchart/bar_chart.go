@@ -2,13 +2,31 @@ package barchart
 
 import (
 	"errors"
-	"strconv"
 	"strings"
 )
 
+const (
+	barMarker        = "*"
+	incompleteMarker = "."
+	estimatedMarker  = "~"
+	// gapLabel stands in for the value of a bar that has none
+	gapLabel = "?"
+	// the unicode markers, which can draw eighths of a character
+	fullBlock       = "█"
+	incompleteBlock = "░"
+	estimatedBlock  = "▒"
+)
+
+// partialBlocks are the eighths of a block, from none to seven eighths
+var partialBlocks = []string{"", "▏", "▎", "▍", "▌", "▋", "▊", "▉"}
+
 type BarChart struct {
-	title string
-	bars []Bar
+	title   string
+	bars    []Bar
+	format  Formatter
+	unicode bool
+	colour  bool
+	note    string
 }
 
 func NewBarChart(title string, bars []Bar) (BarChart, error) {
@@ -16,37 +34,76 @@ func NewBarChart(title string, bars []Bar) (BarChart, error) {
 		return BarChart{}, errors.New("there are no bars in the bar chart")
 	}
 
-	return BarChart{title: title, bars: bars}, nil
+	return BarChart{title: title, bars: bars, format: IntegerFormat}, nil
+}
+
+// WithFormatter returns a copy of the chart that shows its values with the given formatter
+func (b BarChart) WithFormatter(format Formatter) BarChart {
+	b.format = format
+	return b
+}
+
+// WithUnicode returns a copy of the chart that draws its bars with unicode blocks, which show
+// eighths of a character rather than rounding down to whole ones
+func (b BarChart) WithUnicode() BarChart {
+	b.unicode = true
+	return b
+}
+
+// WithColour returns a copy of the chart that colours the bars with ANSI escape codes, by whether
+// they're up or down on the week before
+func (b BarChart) WithColour() BarChart {
+	b.colour = true
+	return b
 }
 
+// WithNote returns a copy of the chart with a note under it, e.g. saying how gaps were filled
+func (b BarChart) WithNote(note string) BarChart {
+	b.note = note
+	return b
+}
+
+func (b BarChart) Bars() []Bar {
+	return b.bars
+}
+
+// PlotToWidth scales the bars so the longest line of the chart is width characters wide
+func (b BarChart) PlotToWidth(width int) string {
+	return b.Plot(CalculateScaleFactor(b.bars, availableWidth(width, b.yAxisWidth())))
+}
+
+// yAxisWidth is the width of everything before the bars, e.g. "25/12 (100) | "
+func (b BarChart) yAxisWidth() int {
+	return longestLabel(b.bars) + len(" (") + widestValue(b.bars, b.format) + len(") | ")
+}
 
 func (b BarChart) Plot(scaleFactor float64) string {
 	var plotted string
 
-	highestCount := 0
-	for _, bar := range b.bars {
-		if bar.count > highestCount {
-			highestCount = bar.count
-		}
-	}
-
-	xAxis := int(float64(highestCount) * scaleFactor) + 1
+	labelWidth := longestLabel(b.bars)
+	valueWidth := widestValue(b.bars, b.format)
+	xAxis := scaledLength(highestValue(b.bars), scaleFactor) + 1
 
 	plotted += "\n"
 	plotted += "----- " + b.title + " -----\n"
 	plotted += "\n"
 
-	for _, bar := range b.bars {
-		scaledCount := int(float64(bar.count) * scaleFactor)
-
-		padding := len(strconv.Itoa(highestCount)) - len(strconv.Itoa(bar.count))
-		yAxisLabel := bar.label + " (" + strconv.Itoa(bar.count) + ") " + strings.Repeat(" ", padding) + "| "
+	hasIncomplete, hasEstimated, hasGap := false, false, false
+	for i, bar := range b.bars {
+		value := valueLabel(bar, b.format)
+		padding := valueWidth - len(value)
+		yAxisLabel := padLabel(bar.label, labelWidth) + " (" + value + ") " + strings.Repeat(" ", padding) + "| "
 		plotted += yAxisLabel
 
-		for x := 0; x < scaledCount; x++ {
-			plotted += "*"
+		drawn, length := b.draw(bar, scaleFactor)
+		hasIncomplete = hasIncomplete || bar.incomplete
+		hasEstimated = hasEstimated || bar.estimated
+		hasGap = hasGap || bar.gap
+		if b.colour {
+			drawn = paint(drawn, barColours(b.bars, i)...)
 		}
-		for x := scaledCount; x <= xAxis; x++ {
+		plotted += drawn
+		for x := length; x <= xAxis; x++ {
 			plotted += " "
 		}
 		plotted += "\n"
@@ -54,6 +111,66 @@ func (b BarChart) Plot(scaleFactor float64) string {
 
 	plotted += "\n"
 
+	if hasIncomplete {
+		marker := incompleteMarker
+		if b.unicode {
+			marker = incompleteBlock
+		}
+		plotted += "Legend: " + marker + " still being reported, likely to rise\n"
+		plotted += "\n"
+	}
+
+	if hasEstimated {
+		marker := estimatedMarker
+		if b.unicode {
+			marker = estimatedBlock
+		}
+		plotted += "Legend: " + marker + " estimated, the figure's missing\n"
+		plotted += "\n"
+	}
+
+	if hasGap {
+		plotted += "Legend: " + gapLabel + " the figure's missing\n"
+		plotted += "\n"
+	}
+
+	if b.colour {
+		plotted += colourLegend()
+		plotted += "\n"
+	}
+
+	if b.note != "" {
+		plotted += b.note + "\n"
+		plotted += "\n"
+	}
+
 	return plotted
 }
 
+// draw returns the bar and how many characters wide it is
+func (b BarChart) draw(bar Bar, scaleFactor float64) (string, int) {
+	scaledCount := scaledLength(bar.value, scaleFactor)
+
+	if !b.unicode {
+		marker := barMarker
+		if bar.estimated {
+			marker = estimatedMarker
+		} else if bar.incomplete {
+			marker = incompleteMarker
+		}
+		return strings.Repeat(marker, scaledCount), scaledCount
+	}
+
+	if bar.estimated {
+		return strings.Repeat(estimatedBlock, scaledCount), scaledCount
+	}
+	if bar.incomplete {
+		return strings.Repeat(incompleteBlock, scaledCount), scaledCount
+	}
+
+	eighths := scaledLength(bar.value, scaleFactor*8) % 8
+	if eighths == 0 {
+		return strings.Repeat(fullBlock, scaledCount), scaledCount
+	}
+	return strings.Repeat(fullBlock, scaledCount) + partialBlocks[eighths], scaledCount + 1
+}
@@ -26,11 +26,11 @@ func TestBarChartPlotsForMultipleBarsWhereScaleFactorIsLessThanOne(t *testing.T)
 	}
 
 	e := "\n----- Data about something or other -----\n\n" +
-		"25th  (10) | *****                      \n" +
-		"26th  (25) | ************               \n" +
-		"27th  (50) | *************************  \n" +
-		"28th  (30) | ***************            \n" +
-		"29th  (1)  |                            \n\n"
+		"25th Dec (10) | *****                      \n" +
+		"26th Dec (25) | ************               \n" +
+		"27th Dec (50) | *************************  \n" +
+		"28th Dec (30) | ***************            \n" +
+		"29th Dec (1)  |                            \n\n"
 
 	plotted := chart.Plot(0.5)
 
@@ -53,11 +53,11 @@ func TestBarChartPlotsForMultipleBarsWhereScaleFactorEqualsOne(t *testing.T) {
 	}
 
 	e := "\n----- Data about something or other -----\n\n" +
-		"25th  (10) | **********                                          \n" +
-		"26th  (25) | *************************                           \n" +
-		"27th  (50) | **************************************************  \n" +
-		"28th  (30) | ******************************                      \n" +
-		"29th  (1)  | *                                                   \n\n"
+		"25th Dec (10) | **********                                          \n" +
+		"26th Dec (25) | *************************                           \n" +
+		"27th Dec (50) | **************************************************  \n" +
+		"28th Dec (30) | ******************************                      \n" +
+		"29th Dec (1)  | *                                                   \n\n"
 
 	plotted := chart.Plot(1.0)
 
@@ -77,11 +77,167 @@ func TestBarChartPlotsForOneBar(t *testing.T) {
 	}
 
 	expected := "\n----- Data about something or other -----\n\n" +
-		"1st J (100) | " +
+		"1st Jan (100) | " +
 		"****************************************************************************************************  \n\n"
 	plotted := chart.Plot(1.0)
 
 	if plotted != expected {
 		t.Fatalf("Expected: %s\n\n Got: %s\n\n", expected, plotted)
 	}
-}
\ No newline at end of file
+}
+
+func TestBarChartPlotsIncompleteBarsDifferently(t *testing.T) {
+	bars := make([]Bar, 0)
+	bars = append(bars, NewBar("1st", 4))
+	bars = append(bars, NewBar("2nd", 3).MarkIncomplete())
+
+	chart, err := NewBarChart("Cases by specimen date", bars)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "\n----- Cases by specimen date -----\n\n" +
+		"1st (4) | ****  \n" +
+		"2nd (3) | ...   \n\n" +
+		"Legend: . still being reported, likely to rise\n\n"
+	plotted := chart.Plot(1.0)
+
+	if plotted != expected {
+		t.Fatalf("Expected: %s\n\n Got: %s\n\n", expected, plotted)
+	}
+}
+
+func TestBarChartPlotsDecimalBars(t *testing.T) {
+	bars := make([]Bar, 0)
+	bars = append(bars, NewBar("1st", 4.25))
+	bars = append(bars, NewBar("2nd", 12.5))
+
+	chart, err := NewBarChart("Cases per 100k", bars)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chart = chart.WithFormatter(DecimalFormat(1))
+
+	expected := "\n----- Cases per 100k -----\n\n" +
+		"1st (4.2)  | ****          \n" +
+		"2nd (12.5) | ************  \n\n"
+	plotted := chart.Plot(1.0)
+
+	if plotted != expected {
+		t.Fatalf("Expected: %s\n\n Got: %s\n\n", expected, plotted)
+	}
+}
+
+func TestBarChartPlotsFractionalValuesWithTwoDecimals(t *testing.T) {
+	bars := make([]Bar, 0)
+	bars = append(bars, NewBar("1st", 0.5))
+	bars = append(bars, NewBar("2nd", 1.25))
+	bars = append(bars, NewBar("3rd", 10.125))
+
+	chart, err := NewBarChart("Fractions", bars)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chart = chart.WithFormatter(DecimalFormat(2))
+
+	expected := "\n----- Fractions -----\n\n" +
+		"1st (0.50)  | **                                        \n" +
+		"2nd (1.25)  | *****                                     \n" +
+		"3rd (10.12) | ****************************************  \n\n"
+	plotted := chart.Plot(4.0)
+
+	if plotted != expected {
+		t.Fatalf("Expected: %s\n\n Got: %s\n\n", expected, plotted)
+	}
+}
+
+func TestBarChartAlignsValuesByTheirFormattedWidth(t *testing.T) {
+	bars := make([]Bar, 0)
+	bars = append(bars, NewBar("1st", 999))
+	bars = append(bars, NewBar("2nd", 1500))
+
+	chart, err := NewBarChart("Cases", bars)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chart = chart.WithFormatter(ThousandsFormat)
+
+	expected := "\n----- Cases -----\n\n" +
+		"1st (999)   | *******      \n" +
+		"2nd (1,500) | ***********  \n\n"
+	plotted := chart.Plot(0.0078125)
+
+	if plotted != expected {
+		t.Fatalf("Expected: %s\n\n Got: %s\n\n", expected, plotted)
+	}
+}
+
+func TestBarChartPlotsToWidth(t *testing.T) {
+	bars := make([]Bar, 0)
+	bars = append(bars, NewBar("1st", 20))
+	bars = append(bars, NewBar("2nd", 10))
+
+	chart, err := NewBarChart("Cases", bars)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "\n----- Cases -----\n\n" +
+		"1st (20) | *******************  \n" +
+		"2nd (10) | *********            \n\n"
+	plotted := chart.PlotToWidth(32)
+
+	if plotted != expected {
+		t.Fatalf("Expected: %s\n\n Got: %s\n\n", expected, plotted)
+	}
+}
+
+func TestBarChartWithUnicodeDrawsEighthsOfABlock(t *testing.T) {
+	bars := make([]Bar, 0)
+	bars = append(bars, NewBar("1st", 4))
+	bars = append(bars, NewBar("2nd", 2.5))
+	bars = append(bars, NewBar("3rd", 2).MarkIncomplete())
+
+	chart, err := NewBarChart("Cases", bars)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chart = chart.WithUnicode()
+
+	expected := "\n----- Cases -----\n\n" +
+		"1st (4) | ████  \n" +
+		"2nd (3) | ██▌   \n" +
+		"3rd (2) | ░░    \n\n" +
+		"Legend: ░ still being reported, likely to rise\n\n"
+	plotted := chart.Plot(1.0)
+
+	if plotted != expected {
+		t.Fatalf("Expected: %s\n\n Got: %s\n\n", expected, plotted)
+	}
+}
+
+func TestBarChartMarksEstimatesAndGaps(t *testing.T) {
+	bars := make([]Bar, 0)
+	bars = append(bars, NewBar("1st", 4))
+	bars = append(bars, NewBar("2nd", 3).MarkEstimated())
+	bars = append(bars, NewBar("3rd", 0).MarkGap())
+
+	chart, err := NewBarChart("Cases", bars)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chart = chart.WithNote("Gaps: the api is missing 1 figure")
+
+	expected := "\n----- Cases -----\n\n" +
+		"1st (4)  | ****  \n" +
+		"2nd (~3) | ~~~   \n" +
+		"3rd (?)  |       \n\n" +
+		"Legend: ~ estimated, the figure's missing\n\n" +
+		"Legend: ? the figure's missing\n\n" +
+		"Gaps: the api is missing 1 figure\n\n"
+	plotted := chart.Plot(1.0)
+
+	if plotted != expected {
+		t.Fatalf("Expected: %s\n\n Got: %s\n\n", expected, plotted)
+	}
+}
@@ -4,32 +4,48 @@ import (
 	"testing"
 )
 
-func TestNewBarTrimsLabelIfLongerThanFiveChars(t *testing.T) {
-	bar := NewBar("123456", 500)
+func TestNewBarKeepsTheWholeLabel(t *testing.T) {
+	bar := NewBar("25th December", 500)
 
-	if bar.Label() != "12345" {
-		t.Fatalf("Label '%s' should have been trimmed to 5 characters", bar.Label())
+	if bar.Label() != "25th December" {
+		t.Fatalf("Label '%s' shouldn't have been trimmed", bar.Label())
 	}
 }
 
-func TestNewBarAddsPaddingToLabelsWithLessThanFourChars(t *testing.T) {
-	bar := NewBar("1234", 500)
-	if bar.Label() != "1234 " {
-		t.Fatalf("Label '%s' should have been whitespace-padded to five chars", bar.Label())
+func TestPadLabelCentresTheLabelInTheGivenWidth(t *testing.T) {
+	if label := padLabel("1234", 5); label != "1234 " {
+		t.Fatalf("Label '%s' should have been whitespace-padded to five chars", label)
 	}
 
-	bar = NewBar("123", 500)
-	if bar.Label() != " 123 " {
-		t.Fatalf("Label '%s' should have been whitespace-padded to five chars", bar.Label())
+	if label := padLabel("123", 5); label != " 123 " {
+		t.Fatalf("Label '%s' should have been whitespace-padded to five chars", label)
 	}
 
-	bar = NewBar("1", 500)
-	if bar.Label() != "  1  " {
-		t.Fatalf("Label '%s' should have been whitespace-padded to five chars", bar.Label())
+	if label := padLabel("1", 5); label != "  1  " {
+		t.Fatalf("Label '%s' should have been whitespace-padded to five chars", label)
 	}
 
-	bar = NewBar("", 500)
-	if bar.Label() != "     " {
-		t.Fatalf("Label '%s' should have been whitespace-padded to five chars", bar.Label())
+	if label := padLabel("", 5); label != "     " {
+		t.Fatalf("Label '%s' should have been whitespace-padded to five chars", label)
 	}
-}
\ No newline at end of file
+}
+
+func TestBarChartPadsLabelsToTheLongestLabel(t *testing.T) {
+	bars := make([]Bar, 0)
+	bars = append(bars, NewBar("1st Jan", 2))
+	bars = append(bars, NewBar("2nd", 1))
+
+	chart, err := NewBarChart("title", bars)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "\n----- title -----\n\n" +
+		"1st Jan (2) | **  \n" +
+		"  2nd   (1) | *   \n\n"
+	plotted := chart.Plot(1.0)
+
+	if plotted != expected {
+		t.Fatalf("Expected: %s\n\n Got: %s\n\n", expected, plotted)
+	}
+}
@@ -0,0 +1,64 @@
+package barchart
+
+import "strings"
+
+// ANSI SGR codes for the colours the charts use
+const (
+	bold    = "1"
+	dim     = "2"
+	red     = "31"
+	green   = "32"
+	yellow  = "33"
+	blue    = "34"
+	magenta = "35"
+	cyan    = "36"
+)
+
+// bars are usually days, so each one is coloured by whether it's up or down on the same day a week
+// before, which isn't thrown off by fewer figures being published at weekends
+const trendLag = 7
+
+// seriesColours go with the seriesMarkers, the markers stay so the series can still be told apart
+// when the colour is stripped
+var seriesColours = []string{cyan, magenta, yellow, blue, green, red, bold}
+
+// paint wraps text in the escape codes for the given colours. It's done after the padding is worked
+// out, as the escape codes take up no room on the screen
+func paint(text string, colours ...string) string {
+	if text == "" || len(colours) == 0 {
+		return text
+	}
+	return "\x1b[" + strings.Join(colours, ";") + "m" + text + "\x1b[0m"
+}
+
+// barColours are the colours the bar at index i is drawn in. Incomplete and estimated bars are
+// dimmed, the rest are red when they're higher than a week before and green when they're lower. The
+// peak is bold
+func barColours(bars []Bar, i int) []string {
+	bar := bars[i]
+	if bar.incomplete || bar.estimated {
+		return []string{dim}
+	}
+
+	var colours []string
+	if bar.value == highestValue(bars) && bar.value > 0 {
+		colours = append(colours, bold)
+	}
+
+	if i >= trendLag {
+		weekBefore := bars[i-trendLag].value
+		if bar.value > weekBefore {
+			colours = append(colours, red)
+		} else if bar.value < weekBefore {
+			colours = append(colours, green)
+		}
+	}
+
+	return colours
+}
+
+// colourLegend explains the colours in a chart that uses barColours
+func colourLegend() string {
+	return "Colours: " + paint("up", red) + " or " + paint("down", green) + " on a week before, " +
+		paint("the peak", bold) + ", " + paint("still being reported", dim) + "\n"
+}
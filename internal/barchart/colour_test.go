@@ -0,0 +1,89 @@
+package barchart
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var escapeCodes = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+func TestBarColoursByWeekOnWeekChange(t *testing.T) {
+	bars := make([]Bar, 0)
+	for _, value := range []float64{5, 5, 5, 5, 5, 5, 5, 6, 4, 5, 9} {
+		bars = append(bars, NewBar("d", value))
+	}
+	bars[10] = bars[10].MarkIncomplete()
+
+	expected := map[int][]string{
+		6:  nil,
+		7:  {red},
+		8:  {green},
+		9:  nil,
+		10: {dim},
+	}
+
+	for i, colours := range expected {
+		if got := barColours(bars, i); strings.Join(got, ";") != strings.Join(colours, ";") {
+			t.Errorf("Expected bar %d to be coloured %v but got %v", i, colours, got)
+		}
+	}
+}
+
+func TestBarColoursMakeThePeakBold(t *testing.T) {
+	bars := []Bar{NewBar("1st", 2), NewBar("2nd", 8), NewBar("3rd", 4)}
+
+	if colours := barColours(bars, 1); len(colours) != 1 || colours[0] != bold {
+		t.Fatalf("Expected the peak to be bold but got %v", colours)
+	}
+}
+
+func TestBarChartWithColourKeepsItsAlignment(t *testing.T) {
+	bars := make([]Bar, 0)
+	for i := 0; i < 10; i++ {
+		bars = append(bars, NewBar("d", float64(i%4)))
+	}
+	bars[9] = bars[9].MarkIncomplete()
+
+	chart, err := NewBarChart("Cases", bars)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plain := chart.PlotToWidth(40)
+	coloured := chart.WithColour().PlotToWidth(40)
+
+	if !strings.Contains(coloured, "\x1b[") {
+		t.Fatalf("Expected escape codes in the coloured chart, got %s", coloured)
+	}
+
+	// the only other difference is the colour legend at the end
+	stripped := strings.Split(escapeCodes.ReplaceAllString(coloured, ""), "\n")
+	for i, line := range strings.Split(strings.TrimSuffix(plain, "\n"), "\n") {
+		if stripped[i] != line {
+			t.Fatalf("Expected line %d to be '%s' without the colour, but got '%s'", i, line, stripped[i])
+		}
+	}
+}
+
+func TestGroupedBarChartWithColourPaintsEachSeries(t *testing.T) {
+	series := []Series{
+		NewSeries("London", []Bar{NewBar("1st", 10)}),
+		NewSeries("Leeds", []Bar{NewBar("1st", 5)}),
+	}
+
+	chart, err := NewGroupedBarChart("Cases", series)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plotted := chart.WithColour().PlotToWidth(40)
+
+	if !strings.Contains(plotted, paint("##########", magenta)) || !strings.Contains(plotted, paint("*", cyan)+" London") {
+		t.Fatalf("Expected each series in its own colour, got %s", plotted)
+	}
+
+	if escapeCodes.ReplaceAllString(plotted, "") != chart.PlotToWidth(40) {
+		t.Fatalf("Expected the colour to be the only difference, got %s", plotted)
+	}
+}
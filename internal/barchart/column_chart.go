@@ -0,0 +1,235 @@
+package barchart
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	// DefaultHeight is how many rows tall the columns can be unless told otherwise
+	DefaultHeight = 15
+	// a row of the y-axis is labelled every this many rows, counting down from the top
+	tickEvery = 5
+	// the widest a column and the space after it can be, so a few days don't fill the whole terminal
+	maxSlotWidth = 4
+)
+
+// ColumnChart draws the bars as vertical columns with the labels along the bottom, which fits a
+// lot more days on the screen than one row per bar
+type ColumnChart struct {
+	title  string
+	bars   []Bar
+	format Formatter
+	height int
+	colour bool
+	note   string
+}
+
+func NewColumnChart(title string, bars []Bar) (ColumnChart, error) {
+	if len(bars) < 1 {
+		return ColumnChart{}, errors.New("there are no bars in the column chart")
+	}
+
+	return ColumnChart{title: title, bars: bars, format: IntegerFormat, height: DefaultHeight}, nil
+}
+
+// WithFormatter returns a copy of the chart that labels the y-axis with the given formatter
+func (c ColumnChart) WithFormatter(format Formatter) ColumnChart {
+	c.format = format
+	return c
+}
+
+// WithHeight returns a copy of the chart whose tallest column is height rows tall
+func (c ColumnChart) WithHeight(height int) ColumnChart {
+	if height < 1 {
+		height = 1
+	}
+	c.height = height
+	return c
+}
+
+// WithColour returns a copy of the chart that colours the columns with ANSI escape codes, by
+// whether they're up or down on the week before
+func (c ColumnChart) WithColour() ColumnChart {
+	c.colour = true
+	return c
+}
+
+// WithNote returns a copy of the chart with a note under it, e.g. saying how gaps were filled
+func (c ColumnChart) WithNote(note string) ColumnChart {
+	c.note = note
+	return c
+}
+
+func (c ColumnChart) Bars() []Bar {
+	return c.bars
+}
+
+// PlotToWidth draws the chart so it fits in width characters. When there are more bars than
+// columns fit, neighbouring bars are averaged into one column
+func (c ColumnChart) PlotToWidth(width int) string {
+	var plotted string
+
+	tickWidth := c.tickWidth()
+	columns := int(availableWidth(width, tickWidth+len(" | ")))
+	buckets, bucketSize := bucket(c.bars, columns)
+
+	slotWidth := columns / len(buckets)
+	if slotWidth > maxSlotWidth {
+		slotWidth = maxSlotWidth
+	}
+	if slotWidth < 1 {
+		slotWidth = 1
+	}
+	columnWidth := slotWidth - 1
+	if columnWidth < 1 {
+		columnWidth = 1
+	}
+
+	highest := highestValue(buckets)
+	scaleFactor := CalculateScaleFactor(buckets, float64(c.height))
+
+	plotted += "\n"
+	plotted += "----- " + c.title + " -----\n"
+	plotted += "\n"
+
+	hasIncomplete, hasEstimated, hasGap := false, false, false
+	for _, bar := range buckets {
+		hasIncomplete = hasIncomplete || bar.incomplete
+		hasEstimated = hasEstimated || bar.estimated
+		hasGap = hasGap || bar.gap
+	}
+
+	for row := c.height; row >= 1; row-- {
+		tick := ""
+		if (c.height-row)%tickEvery == 0 {
+			tick = c.format(highest * float64(row) / float64(c.height))
+		}
+		line := strings.Repeat(" ", tickWidth-len(tick)) + tick + " | "
+
+		for i, bar := range buckets {
+			column := strings.Repeat(" ", columnWidth)
+			if !bar.gap && scaledLength(bar.value, scaleFactor) >= row {
+				marker := barMarker
+				if bar.estimated {
+					marker = estimatedMarker
+				} else if bar.incomplete {
+					marker = incompleteMarker
+				}
+				column = strings.Repeat(marker, columnWidth)
+				if c.colour {
+					column = paint(column, barColours(buckets, i)...)
+				}
+			}
+			line += column + strings.Repeat(" ", slotWidth-columnWidth)
+		}
+		plotted += strings.TrimRight(line, " ") + "\n"
+	}
+
+	zero := c.format(0)
+	plotted += strings.Repeat(" ", tickWidth-len(zero)) + zero + " +" + strings.Repeat("-", len(buckets)*slotWidth) + "\n"
+	plotted += strings.Repeat(" ", tickWidth+len(" | ")) + xAxisLabels(buckets, slotWidth) + "\n"
+	plotted += "\n"
+
+	if bucketSize > 1 {
+		plotted += fmt.Sprintf("Each column is the average of %d values, labelled with the first\n", bucketSize)
+		plotted += "\n"
+	}
+
+	if hasIncomplete {
+		plotted += "Legend: " + incompleteMarker + " still being reported, likely to rise\n"
+		plotted += "\n"
+	}
+
+	if hasEstimated {
+		plotted += "Legend: " + estimatedMarker + " estimated, the figure's missing\n"
+		plotted += "\n"
+	}
+
+	if hasGap {
+		plotted += "Legend: an empty column, the figure's missing\n"
+		plotted += "\n"
+	}
+
+	if c.colour {
+		plotted += colourLegend()
+		plotted += "\n"
+	}
+
+	if c.note != "" {
+		plotted += c.note + "\n"
+		plotted += "\n"
+	}
+
+	return plotted
+}
+
+// tickWidth is the width of the widest y-axis label
+func (c ColumnChart) tickWidth() int {
+	highest := highestValue(c.bars)
+	widest := len(c.format(0))
+	for row := c.height; row >= 1; row -= tickEvery {
+		if tick := c.format(highest * float64(row) / float64(c.height)); len(tick) > widest {
+			widest = len(tick)
+		}
+	}
+	return widest
+}
+
+// bucket averages runs of neighbouring bars so there are at most columns of them. Each bucket
+// takes the label of its first bar and is incomplete or estimated if any of its bars are. Gaps are
+// left out of the average, and a bucket that's all gaps is a gap
+func bucket(bars []Bar, columns int) ([]Bar, int) {
+	size := (len(bars) + columns - 1) / columns
+	if size <= 1 {
+		return bars, 1
+	}
+
+	var buckets []Bar
+	for start := 0; start < len(bars); start += size {
+		end := start + size
+		if end > len(bars) {
+			end = len(bars)
+		}
+
+		total := 0.0
+		counted := 0
+		incomplete, estimated := false, false
+		for _, bar := range bars[start:end] {
+			incomplete = incomplete || bar.incomplete
+			estimated = estimated || bar.estimated
+			if !bar.gap {
+				total += bar.value
+				counted++
+			}
+		}
+
+		b := NewBar(bars[start].label, 0).MarkGap()
+		if counted > 0 {
+			b = NewBar(bars[start].label, total/float64(counted))
+		}
+		b.incomplete = incomplete
+		b.estimated = estimated
+		buckets = append(buckets, b)
+	}
+
+	return buckets, size
+}
+
+// xAxisLabels spaces the labels out under their columns, skipping columns where a label would
+// run into the one before it or past the end of the chart
+func xAxisLabels(bars []Bar, slotWidth int) string {
+	labelWidth := longestLabel(bars) + 1
+	every := (labelWidth + slotWidth - 1) / slotWidth
+	end := len(bars)*slotWidth + 2
+
+	line := []byte(strings.Repeat(" ", end))
+	for i := 0; i < len(bars); i += every {
+		if i*slotWidth+len(bars[i].label) <= end {
+			copy(line[i*slotWidth:], bars[i].label)
+		}
+	}
+
+	return strings.TrimRight(string(line), " ")
+}
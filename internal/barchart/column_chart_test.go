@@ -0,0 +1,105 @@
+package barchart
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewColumnChartThrowsErrorIfThereAreNoBars(t *testing.T) {
+	_, err := NewColumnChart("title", make([]Bar, 0))
+
+	if err == nil {
+		t.Fatalf("NewColumnChart() should throw an error if there are no bars")
+	}
+}
+
+func TestColumnChartPlotsColumnsWithTicksAndLabels(t *testing.T) {
+	bars := make([]Bar, 0)
+	bars = append(bars, NewBar("1st", 2))
+	bars = append(bars, NewBar("2nd", 6))
+	bars = append(bars, NewBar("3rd", 10))
+	bars = append(bars, NewBar("4th", 4).MarkIncomplete())
+
+	chart, err := NewColumnChart("Cases", bars)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "\n----- Cases -----\n\n" +
+		"10 |         ***\n" +
+		"   |         ***\n" +
+		"   |     *** ***\n" +
+		"   |     *** *** ...\n" +
+		"   | *** *** *** ...\n" +
+		" 0 +----------------\n" +
+		"     1st 2nd 3rd 4th\n\n" +
+		"Legend: . still being reported, likely to rise\n\n"
+	plotted := chart.WithHeight(5).PlotToWidth(80)
+
+	if plotted != expected {
+		t.Fatalf("Expected: %s\n\n Got: %s\n\n", expected, plotted)
+	}
+}
+
+func TestColumnChartAveragesBarsWhenThereAreMoreThanFit(t *testing.T) {
+	bars := make([]Bar, 0)
+	for i := 0; i < 20; i++ {
+		bars = append(bars, NewBar("d", float64(i%2*10)))
+	}
+
+	chart, err := NewColumnChart("Cases", bars)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 10 is 2 wide, leaving 10 columns once the y-axis and padding are taken off
+	plotted := chart.WithHeight(2).PlotToWidth(17)
+
+	if !strings.Contains(plotted, " 5 | **********\n") {
+		t.Fatalf("Expected every pair of 0 and 10 to be averaged to 5, got %s", plotted)
+	}
+
+	if !strings.Contains(plotted, "Each column is the average of 2 values") {
+		t.Fatalf("Expected the chart to say the columns are averaged, got %s", plotted)
+	}
+}
+
+func TestColumnChartLeavesGapsEmpty(t *testing.T) {
+	bars := make([]Bar, 0)
+	bars = append(bars, NewBar("1st", 2))
+	bars = append(bars, NewBar("2nd", 0).MarkGap())
+	bars = append(bars, NewBar("3rd", 4).MarkEstimated())
+
+	chart, err := NewColumnChart("Cases", bars)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "\n----- Cases -----\n\n" +
+		"4 |         ~~~\n" +
+		"  |         ~~~\n" +
+		"  | ***     ~~~\n" +
+		"  | ***     ~~~\n" +
+		"0 +------------\n" +
+		"    1st 2nd 3rd\n\n" +
+		"Legend: ~ estimated, the figure's missing\n\n" +
+		"Legend: an empty column, the figure's missing\n\n"
+	plotted := chart.WithHeight(4).PlotToWidth(80)
+
+	if plotted != expected {
+		t.Fatalf("Expected: %s\n\n Got: %s\n\n", expected, plotted)
+	}
+}
+
+func TestBucketLeavesGapsOutOfTheAverage(t *testing.T) {
+	bars := []Bar{NewBar("1st", 2), NewBar("2nd", 0).MarkGap(), NewBar("3rd", 0).MarkGap(), NewBar("4th", 0).MarkGap()}
+
+	buckets, _ := bucket(bars, 2)
+
+	if buckets[0].value != 2 || buckets[0].gap {
+		t.Errorf("the first bucket should average only the figure it has, got %+v", buckets[0])
+	}
+	if !buckets[1].gap {
+		t.Errorf("a bucket of only gaps should be a gap, got %+v", buckets[1])
+	}
+}
@@ -0,0 +1,99 @@
+package barchart
+
+import (
+	"image"
+	"image/color"
+	"strings"
+)
+
+// the standard library has no fonts, so the PNG charts are labelled with this 3x5 pixel one. It has
+// digits, capitals and the punctuation the charts use, lower case letters are drawn as capitals
+var glyphs = map[rune][5]string{
+	'0':  {"111", "101", "101", "101", "111"},
+	'1':  {"010", "110", "010", "010", "111"},
+	'2':  {"111", "001", "111", "100", "111"},
+	'3':  {"111", "001", "111", "001", "111"},
+	'4':  {"101", "101", "111", "001", "001"},
+	'5':  {"111", "100", "111", "001", "111"},
+	'6':  {"111", "100", "111", "101", "111"},
+	'7':  {"111", "001", "001", "001", "001"},
+	'8':  {"111", "101", "111", "101", "111"},
+	'9':  {"111", "101", "111", "001", "111"},
+	'A':  {"010", "101", "111", "101", "101"},
+	'B':  {"110", "101", "110", "101", "110"},
+	'C':  {"011", "100", "100", "100", "011"},
+	'D':  {"110", "101", "101", "101", "110"},
+	'E':  {"111", "100", "110", "100", "111"},
+	'F':  {"111", "100", "110", "100", "100"},
+	'G':  {"011", "100", "101", "101", "011"},
+	'H':  {"101", "101", "111", "101", "101"},
+	'I':  {"111", "010", "010", "010", "111"},
+	'J':  {"001", "001", "001", "101", "010"},
+	'K':  {"101", "101", "110", "101", "101"},
+	'L':  {"100", "100", "100", "100", "111"},
+	'M':  {"101", "111", "111", "101", "101"},
+	'N':  {"110", "101", "101", "101", "101"},
+	'O':  {"010", "101", "101", "101", "010"},
+	'P':  {"110", "101", "110", "100", "100"},
+	'Q':  {"010", "101", "101", "110", "011"},
+	'R':  {"110", "101", "110", "101", "101"},
+	'S':  {"011", "100", "010", "001", "110"},
+	'T':  {"111", "010", "010", "010", "010"},
+	'U':  {"101", "101", "101", "101", "111"},
+	'V':  {"101", "101", "101", "101", "010"},
+	'W':  {"101", "101", "111", "111", "101"},
+	'X':  {"101", "101", "010", "101", "101"},
+	'Y':  {"101", "101", "010", "010", "010"},
+	'Z':  {"111", "001", "010", "100", "111"},
+	' ':  {"000", "000", "000", "000", "000"},
+	'/':  {"001", "001", "010", "100", "100"},
+	'.':  {"000", "000", "000", "000", "010"},
+	',':  {"000", "000", "000", "010", "100"},
+	'-':  {"000", "000", "111", "000", "000"},
+	':':  {"000", "010", "000", "010", "000"},
+	'(':  {"010", "100", "100", "100", "010"},
+	')':  {"010", "001", "001", "001", "010"},
+	'%':  {"101", "001", "010", "100", "101"},
+	'&':  {"010", "101", "010", "101", "011"},
+	'+':  {"000", "010", "111", "010", "000"},
+	'?':  {"111", "001", "010", "000", "010"},
+	'~':  {"000", "000", "011", "110", "000"},
+	'\'': {"010", "010", "000", "000", "000"},
+}
+
+// glyphScale is how many pixels square each dot of a glyph is. At 2 a glyph and the gap after it
+// are charWidth wide
+const glyphScale = 2
+
+// glyphHeight is how many pixels tall the text is
+const glyphHeight = 5 * glyphScale
+
+// drawText draws text with its top left corner at x, y
+func drawText(img *image.RGBA, x int, y int, text string, c color.Color) {
+	for _, r := range strings.ToUpper(text) {
+		glyph, ok := glyphs[r]
+		if !ok {
+			glyph = glyphs['?']
+		}
+
+		for row, dots := range glyph {
+			for column, dot := range dots {
+				if dot != '1' {
+					continue
+				}
+				for dy := 0; dy < glyphScale; dy++ {
+					for dx := 0; dx < glyphScale; dx++ {
+						img.Set(x+column*glyphScale+dx, y+row*glyphScale+dy, c)
+					}
+				}
+			}
+		}
+
+		x += charWidth
+	}
+}
+
+// textWidth is how many pixels wide drawText draws the text
+func textWidth(text string) int {
+	return len([]rune(text)) * charWidth
+}
@@ -0,0 +1,67 @@
+package barchart
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Formatter turns a bar's value into the text shown next to its label
+type Formatter func(value float64) string
+
+// IntegerFormat rounds to a whole number, e.g. 12345
+func IntegerFormat(value float64) string {
+	return strconv.FormatFloat(math.Round(value), 'f', 0, 64)
+}
+
+// ThousandsFormat rounds to a whole number with thousands separators, e.g. 12,345
+func ThousandsFormat(value float64) string {
+	digits := IntegerFormat(math.Abs(value))
+
+	var grouped string
+	for i, digit := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			grouped += ","
+		}
+		grouped += string(digit)
+	}
+
+	if math.Round(value) < 0 {
+		return "-" + grouped
+	}
+	return grouped
+}
+
+// CompactFormat shortens thousands and millions, e.g. 999, 1.2k, 12.3k, 4.5M. The value's rounded to
+// what's shown before the unit is chosen, so 999.95 is 1k rather than 1000
+func CompactFormat(value float64) string {
+	if math.Abs(math.Round(value)) < 1e3 {
+		return IntegerFormat(value)
+	}
+
+	if thousands := roundToTenths(value / 1e3); math.Abs(thousands) < 1e3 {
+		return trimZeroDecimal(strconv.FormatFloat(thousands, 'f', 1, 64)) + "k"
+	}
+	return trimZeroDecimal(strconv.FormatFloat(roundToTenths(value/1e6), 'f', 1, 64)) + "M"
+}
+
+func roundToTenths(value float64) float64 {
+	return math.Round(value*10) / 10
+}
+
+// PercentFormat shows a percentage to one decimal place, e.g. 12.3%
+func PercentFormat(value float64) string {
+	return strconv.FormatFloat(value, 'f', 1, 64) + "%"
+}
+
+// DecimalFormat shows values to a fixed number of decimal places, e.g. 12.34 for 2 places
+func DecimalFormat(places int) Formatter {
+	return func(value float64) string {
+		return strconv.FormatFloat(value, 'f', places, 64)
+	}
+}
+
+// 1.0k reads better as 1k
+func trimZeroDecimal(s string) string {
+	return strings.TrimSuffix(s, ".0")
+}
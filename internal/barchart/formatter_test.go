@@ -0,0 +1,35 @@
+package barchart
+
+import (
+	"testing"
+)
+
+func TestFormatters(t *testing.T) {
+	cases := []struct {
+		name      string
+		formatter Formatter
+		value     float64
+		expected  string
+	}{
+		{"integer", IntegerFormat, 12345.6, "12346"},
+		{"thousands", ThousandsFormat, 1234567, "1,234,567"},
+		{"thousands under a thousand", ThousandsFormat, 999, "999"},
+		{"thousands negative", ThousandsFormat, -12345, "-12,345"},
+		{"compact under a thousand", CompactFormat, 999, "999"},
+		{"compact thousands", CompactFormat, 1234, "1.2k"},
+		{"compact whole thousands", CompactFormat, 12000, "12k"},
+		{"compact millions", CompactFormat, 4560000, "4.6M"},
+		{"compact rounding up to a thousand", CompactFormat, 999.95, "1k"},
+		{"compact rounding up to a million", CompactFormat, 999950, "1M"},
+		{"compact just under a million", CompactFormat, 999949, "999.9k"},
+		{"compact negative rounding up to a thousand", CompactFormat, -999.5, "-1k"},
+		{"percent", PercentFormat, 12.34, "12.3%"},
+		{"two decimals", DecimalFormat(2), 1.5, "1.50"},
+	}
+
+	for _, c := range cases {
+		if formatted := c.formatter(c.value); formatted != c.expected {
+			t.Fatalf("%s: expected %v to be formatted as '%s' but got '%s'", c.name, c.value, c.expected, formatted)
+		}
+	}
+}
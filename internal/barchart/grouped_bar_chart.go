@@ -0,0 +1,184 @@
+package barchart
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// each series is drawn with its own marker so they can be told apart without colour
+var seriesMarkers = []string{"*", "#", "+", "o", "=", "%", "@"}
+
+type Series struct {
+	name string
+	bars []Bar
+}
+
+func NewSeries(name string, bars []Bar) Series {
+	return Series{name, bars}
+}
+
+func (s Series) Name() string {
+	return s.name
+}
+
+func (s Series) Bars() []Bar {
+	return s.bars
+}
+
+// GroupedBarChart draws one bar per series for every label, e.g. the cases in London and Manchester
+// for each day. The bars at the same index in each series share a label
+type GroupedBarChart struct {
+	title  string
+	series []Series
+	format Formatter
+	colour bool
+	note   string
+}
+
+func NewGroupedBarChart(title string, series []Series) (GroupedBarChart, error) {
+	if len(series) < 1 {
+		return GroupedBarChart{}, errors.New("there are no series in the bar chart")
+	}
+
+	if len(series) > len(seriesMarkers) {
+		return GroupedBarChart{}, fmt.Errorf("a bar chart can compare at most %d series", len(seriesMarkers))
+	}
+
+	for _, s := range series {
+		if len(s.bars) < 1 {
+			return GroupedBarChart{}, fmt.Errorf("there are no bars in the '%s' series", s.name)
+		}
+		if len(s.bars) != len(series[0].bars) {
+			return GroupedBarChart{}, fmt.Errorf("the '%s' series has %d bars but '%s' has %d",
+				s.name, len(s.bars), series[0].name, len(series[0].bars))
+		}
+	}
+
+	return GroupedBarChart{title: title, series: series, format: IntegerFormat}, nil
+}
+
+// WithFormatter returns a copy of the chart that shows its values with the given formatter
+func (g GroupedBarChart) WithFormatter(format Formatter) GroupedBarChart {
+	g.format = format
+	return g
+}
+
+// WithColour returns a copy of the chart that draws each series in its own colour
+func (g GroupedBarChart) WithColour() GroupedBarChart {
+	g.colour = true
+	return g
+}
+
+// WithNote returns a copy of the chart with a note under it, e.g. saying how gaps were filled
+func (g GroupedBarChart) WithNote(note string) GroupedBarChart {
+	g.note = note
+	return g
+}
+
+// Bars returns every bar in every series, which is handy for CalculateScaleFactor
+func (g GroupedBarChart) Bars() []Bar {
+	var bars []Bar
+	for _, s := range g.series {
+		bars = append(bars, s.bars...)
+	}
+	return bars
+}
+
+// PlotToWidth scales the bars so the longest line of the chart is width characters wide
+func (g GroupedBarChart) PlotToWidth(width int) string {
+	return g.Plot(CalculateScaleFactor(g.Bars(), availableWidth(width, g.yAxisWidth())))
+}
+
+// yAxisWidth is the width of everything before the bars, e.g. "25/12 London (100) | "
+func (g GroupedBarChart) yAxisWidth() int {
+	return longestLabel(g.Bars()) + len(" ") + g.longestName() + len(" (") + widestValue(g.Bars(), g.format) +
+		len(") | ")
+}
+
+func (g GroupedBarChart) longestName() int {
+	longest := 0
+	for _, s := range g.series {
+		if len(s.name) > longest {
+			longest = len(s.name)
+		}
+	}
+	return longest
+}
+
+func (g GroupedBarChart) Plot(scaleFactor float64) string {
+	var plotted string
+
+	labelWidth := longestLabel(g.Bars())
+	longestName := g.longestName()
+	valueWidth := widestValue(g.Bars(), g.format)
+	xAxis := scaledLength(highestValue(g.Bars()), scaleFactor) + 1
+
+	plotted += "\n"
+	plotted += "----- " + g.title + " -----\n"
+	plotted += "\n"
+
+	for i := range g.series[0].bars {
+		for j, s := range g.series {
+			bar := s.bars[i]
+			scaledCount := scaledLength(bar.value, scaleFactor)
+
+			// only the first bar in each group is labelled, the rest line up underneath it
+			label := padLabel(bar.label, labelWidth)
+			if j > 0 {
+				label = strings.Repeat(" ", labelWidth)
+			}
+
+			namePadding := longestName - len(s.name)
+			value := valueLabel(bar, g.format)
+			valuePadding := valueWidth - len(value)
+			yAxisLabel := label + " " + s.name + strings.Repeat(" ", namePadding) +
+				" (" + value + ") " + strings.Repeat(" ", valuePadding) + "| "
+			plotted += yAxisLabel
+
+			plotted += g.paint(strings.Repeat(seriesMarkers[j], scaledCount), j)
+			plotted += strings.Repeat(" ", xAxis-scaledCount+1)
+			plotted += "\n"
+		}
+	}
+
+	plotted += "\n"
+
+	var legend []string
+	for i, s := range g.series {
+		legend = append(legend, g.paint(seriesMarkers[i], i)+" "+s.name)
+	}
+	plotted += "Legend: " + strings.Join(legend, "  ") + "\n"
+	plotted += "\n"
+
+	hasEstimated, hasGap := false, false
+	for _, bar := range g.Bars() {
+		hasEstimated = hasEstimated || bar.estimated
+		hasGap = hasGap || bar.gap
+	}
+
+	if hasEstimated {
+		plotted += "Legend: " + estimatedMarker + " estimated, the figure's missing\n"
+		plotted += "\n"
+	}
+
+	if hasGap {
+		plotted += "Legend: " + gapLabel + " the figure's missing\n"
+		plotted += "\n"
+	}
+
+	if g.note != "" {
+		plotted += g.note + "\n"
+		plotted += "\n"
+	}
+
+	return plotted
+}
+
+// paint colours text in the colour of the series at index i, if the chart is in colour
+func (g GroupedBarChart) paint(text string, i int) string {
+	if !g.colour {
+		return text
+	}
+	return paint(text, seriesColours[i])
+}
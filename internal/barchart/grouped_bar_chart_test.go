@@ -0,0 +1,114 @@
+package barchart
+
+import (
+	"testing"
+)
+
+func TestNewGroupedBarChartThrowsErrorIfThereAreNoSeries(t *testing.T) {
+	_, err := NewGroupedBarChart("title", make([]Series, 0))
+
+	if err == nil {
+		t.Fatalf("NewGroupedBarChart() should throw an error if there are no series")
+	}
+}
+
+func TestNewGroupedBarChartThrowsErrorIfASeriesHasNoBars(t *testing.T) {
+	series := []Series{
+		NewSeries("London", []Bar{NewBar("1st", 10)}),
+		NewSeries("Manchester", make([]Bar, 0)),
+	}
+
+	_, err := NewGroupedBarChart("title", series)
+
+	if err == nil {
+		t.Fatalf("NewGroupedBarChart() should throw an error if a series has no bars")
+	}
+}
+
+func TestNewGroupedBarChartThrowsErrorIfSeriesHaveDifferentLengths(t *testing.T) {
+	series := []Series{
+		NewSeries("London", []Bar{NewBar("1st", 10), NewBar("2nd", 20)}),
+		NewSeries("Manchester", []Bar{NewBar("1st", 5)}),
+	}
+
+	_, err := NewGroupedBarChart("title", series)
+
+	if err == nil {
+		t.Fatalf("NewGroupedBarChart() should throw an error if the series have a different number of bars")
+	}
+}
+
+func TestGroupedBarChartPlotsOneBarPerSeriesForEachLabel(t *testing.T) {
+	series := []Series{
+		NewSeries("London", []Bar{NewBar("1st", 10), NewBar("2nd", 4)}),
+		NewSeries("Leeds", []Bar{NewBar("1st", 6), NewBar("2nd", 8)}),
+	}
+
+	chart, err := NewGroupedBarChart("Cases", series)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e := "\n----- Cases -----\n\n" +
+		"1st London (10) | **********  \n" +
+		"    Leeds  (6)  | ######      \n" +
+		"2nd London (4)  | ****        \n" +
+		"    Leeds  (8)  | ########    \n\n" +
+		"Legend: * London  # Leeds\n\n"
+
+	plotted := chart.Plot(1.0)
+
+	if plotted != e {
+		t.Fatalf("Expected: %s\n\n Got: %s\n\n", e, plotted)
+	}
+}
+
+func TestGroupedBarChartPlotsToWidth(t *testing.T) {
+	series := []Series{
+		NewSeries("London", []Bar{NewBar("1st", 10)}),
+		NewSeries("Leeds", []Bar{NewBar("1st", 5)}),
+	}
+
+	chart, err := NewGroupedBarChart("Cases", series)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e := "\n----- Cases -----\n\n" +
+		"1st London (10) | ********************  \n" +
+		"    Leeds  (5)  | ##########            \n\n" +
+		"Legend: * London  # Leeds\n\n"
+
+	plotted := chart.PlotToWidth(40)
+
+	if plotted != e {
+		t.Fatalf("Expected: %s\n\n Got: %s\n\n", e, plotted)
+	}
+}
+
+func TestGroupedBarChartExplainsEstimatesAndGaps(t *testing.T) {
+	series := []Series{
+		NewSeries("London", []Bar{NewBar("1st", 10), NewBar("2nd", 4).MarkEstimated()}),
+		NewSeries("Leeds", []Bar{NewBar("1st", 6), NewBar("2nd", 0).MarkGap()}),
+	}
+
+	chart, err := NewGroupedBarChart("Cases", series)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e := "\n----- Cases -----\n\n" +
+		"1st London (10) | **********  \n" +
+		"    Leeds  (6)  | ######      \n" +
+		"2nd London (~4) | ****        \n" +
+		"    Leeds  (?)  |             \n\n" +
+		"Legend: * London  # Leeds\n\n" +
+		"Legend: ~ estimated, the figure's missing\n\n" +
+		"Legend: ? the figure's missing\n\n"
+
+	plotted := chart.Plot(1.0)
+
+	if plotted != e {
+		t.Fatalf("Expected: %s\n\n Got: %s\n\n", e, plotted)
+	}
+}
@@ -0,0 +1,152 @@
+package barchart
+
+import "strings"
+
+// the geometry shared by the SVG and PNG charts, in pixels
+const (
+	imageWidth   = 800
+	imageMargin  = 16
+	titleHeight  = 40
+	rowHeight    = 20
+	barThickness = 14
+	// the room under the bars for the x-axis, its ticks and their labels
+	axisHeight   = 36
+	legendHeight = 24
+	noteHeight   = 18
+	// how wide a character of the labels is, which the fonts are chosen to match
+	charWidth = 8
+	// the number of gaps between the ticks on the x-axis
+	tickCount = 4
+)
+
+// the colours of the SVG and PNG charts
+const (
+	imageBackground = "#ffffff"
+	imageBar        = "#4477aa"
+	imageIncomplete = "#a5bbd4"
+	imageEstimated  = "#ccbb44"
+	// a gap is drawn as an empty outline of gapWidth, as it has no value
+	imageGap = "#999999"
+	imageInk = "#333333"
+)
+
+// gapWidth is how many pixels wide a gap's outline is
+const gapWidth = charWidth * 2
+
+// imageLayout is where everything in an image of a BarChart goes, so the SVG and PNG look the same
+type imageLayout struct {
+	width, height int
+	// the area the bars are drawn in
+	plotLeft, plotRight, plotTop, plotBottom int
+	// pixels per unit of value
+	scale  float64
+	labels []string
+	ticks  []imageTick
+	// the legend has an entry for each kind of bar that's marked, and the note is wrapped to fit
+	legend []imageLegendEntry
+	note   []string
+}
+
+type imageTick struct {
+	x     int
+	label string
+}
+
+type imageLegendEntry struct {
+	colour string
+	// gaps are shown as an outline, like they're drawn
+	gap   bool
+	label string
+}
+
+func (b BarChart) layout() imageLayout {
+	l := imageLayout{width: imageWidth, plotTop: titleHeight}
+
+	longest := 0
+	hasIncomplete, hasEstimated, hasGap := false, false, false
+	for _, bar := range b.bars {
+		label := bar.label + " (" + valueLabel(bar, b.format) + ")"
+		l.labels = append(l.labels, label)
+		if len(label) > longest {
+			longest = len(label)
+		}
+		hasIncomplete = hasIncomplete || bar.incomplete
+		hasEstimated = hasEstimated || bar.estimated
+		hasGap = hasGap || bar.gap
+	}
+
+	// the same legend as the text charts, in the same order
+	if hasIncomplete {
+		l.legend = append(l.legend, imageLegendEntry{colour: imageIncomplete, label: "still being reported, likely to rise"})
+	}
+	if hasEstimated {
+		l.legend = append(l.legend, imageLegendEntry{colour: imageEstimated, label: "estimated, the figure's missing"})
+	}
+	if hasGap {
+		l.legend = append(l.legend, imageLegendEntry{colour: imageGap, gap: true, label: "the figure's missing"})
+	}
+	l.note = wrap(b.note, (imageWidth-2*imageMargin)/charWidth)
+
+	l.plotLeft = imageMargin + longest*charWidth + charWidth
+	// leave room for half the last tick's label
+	l.plotRight = imageWidth - imageMargin - len(b.format(highestValue(b.bars)))*charWidth/2
+	l.plotBottom = l.plotTop + len(b.bars)*rowHeight
+
+	l.height = l.plotBottom + axisHeight + len(l.legend)*legendHeight + len(l.note)*noteHeight + imageMargin
+
+	highest := highestValue(b.bars)
+	if highest > 0 {
+		l.scale = float64(l.plotRight-l.plotLeft) / highest
+	}
+
+	for i := 0; i <= tickCount; i++ {
+		value := highest * float64(i) / tickCount
+		x := l.plotLeft + (l.plotRight-l.plotLeft)*i/tickCount
+		l.ticks = append(l.ticks, imageTick{x, b.format(value)})
+	}
+
+	return l
+}
+
+// barLength is how many pixels long the bar is, negative values aren't drawn
+func (l imageLayout) barLength(value float64) int {
+	if value <= 0 {
+		return 0
+	}
+	return int(value*l.scale + 0.5)
+}
+
+// legendTop is the top of the i'th entry of the legend, under the x-axis
+func (l imageLayout) legendTop(i int) int {
+	return l.plotBottom + axisHeight + i*legendHeight
+}
+
+// noteTop is the top of the i'th line of the note, under the legend
+func (l imageLayout) noteTop(i int) int {
+	return l.legendTop(len(l.legend)) + i*noteHeight
+}
+
+// wrap splits text into lines of at most width characters, breaking between words
+func wrap(text string, width int) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(text) {
+		if line != "" && len(line)+1+len(word) > width {
+			lines = append(lines, line)
+			line = ""
+		}
+		if line != "" {
+			line += " "
+		}
+		line += word
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// rowTop is the top of the i'th row of the chart
+func (l imageLayout) rowTop(i int) int {
+	return l.plotTop + i*rowHeight
+}
@@ -0,0 +1,159 @@
+package barchart
+
+import (
+	"bytes"
+	"flag"
+	"image/png"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata with the current output")
+
+func givenImageChart(t *testing.T) BarChart {
+	bars := make([]Bar, 0)
+	bars = append(bars, NewBar("25/12", 1200))
+	bars = append(bars, NewBar("26/12", 850))
+	bars = append(bars, NewBar("27/12", 400).MarkIncomplete())
+
+	chart, err := NewBarChart("New cases in Leeds & Bradford", bars)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return chart.WithFormatter(ThousandsFormat)
+}
+
+func givenImageChartWithGaps(t *testing.T) BarChart {
+	bars := make([]Bar, 0)
+	bars = append(bars, NewBar("25/12", 1200))
+	bars = append(bars, NewBar("26/12", 850).MarkEstimated())
+	bars = append(bars, NewBar("27/12", 0).MarkGap())
+	bars = append(bars, NewBar("28/12", 400).MarkIncomplete())
+
+	chart, err := NewBarChart("New cases in Leeds", bars)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return chart.WithFormatter(ThousandsFormat).
+		WithNote("The figure for 26/12 is estimated from the days either side, as the api has none for it, and 27/12 is left as a gap")
+}
+
+// assertGolden compares got with the golden file, or rewrites the golden file when run with -update
+func assertGolden(t *testing.T, name string, got []byte) {
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := ioutil.WriteFile(path, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expected, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(got, expected) {
+		t.Fatalf("Expected the contents of %s:\n%s\n\nGot:\n%s", path, expected, got)
+	}
+}
+
+func TestBarChartWriteSVG(t *testing.T) {
+	var svg bytes.Buffer
+
+	if err := givenImageChart(t).WriteSVG(&svg); err != nil {
+		t.Fatal(err)
+	}
+
+	assertGolden(t, "bar_chart.svg", svg.Bytes())
+}
+
+func TestBarChartWriteSVGWithoutIncompleteBars(t *testing.T) {
+	bars := []Bar{NewBar("1st", 0.5), NewBar("2nd", 2.25)}
+	chart, err := NewBarChart("Positivity rate (%)", bars)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var svg bytes.Buffer
+	if err := chart.WithFormatter(PercentFormat).WriteSVG(&svg); err != nil {
+		t.Fatal(err)
+	}
+
+	assertGolden(t, "bar_chart_complete.svg", svg.Bytes())
+}
+
+func TestBarChartWriteSVGWithEstimatesAndGaps(t *testing.T) {
+	var svg bytes.Buffer
+
+	if err := givenImageChartWithGaps(t).WriteSVG(&svg); err != nil {
+		t.Fatal(err)
+	}
+
+	assertGolden(t, "bar_chart_gaps.svg", svg.Bytes())
+}
+
+func TestBarChartWritePNG(t *testing.T) {
+	chart := givenImageChart(t)
+	var out bytes.Buffer
+
+	if err := chart.WritePNG(&out); err != nil {
+		t.Fatal(err)
+	}
+
+	img, err := png.Decode(&out)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l := chart.layout()
+	if img.Bounds().Dx() != l.width || img.Bounds().Dy() != l.height {
+		t.Fatalf("Expected a %dx%d image but got %v", l.width, l.height, img.Bounds())
+	}
+
+	// the middle of the first bar is the bar colour and the end of the longest one is the full width
+	middle := l.rowTop(0) + rowHeight/2
+	if got := img.At(l.plotLeft+10, middle); got != hexColour(imageBar) {
+		t.Fatalf("Expected the first bar to be drawn in %s but got %v", imageBar, got)
+	}
+	if got := img.At(l.plotRight-1, middle); got != hexColour(imageBar) {
+		t.Fatalf("Expected the longest bar to reach the right of the plot but got %v", got)
+	}
+	if got := img.At(l.plotLeft+10, l.rowTop(2)+rowHeight/2); got != hexColour(imageIncomplete) {
+		t.Fatalf("Expected the incomplete bar to be drawn in %s but got %v", imageIncomplete, got)
+	}
+}
+
+func TestBarChartWritePNGWithEstimatesAndGaps(t *testing.T) {
+	chart := givenImageChartWithGaps(t)
+	var out bytes.Buffer
+
+	if err := chart.WritePNG(&out); err != nil {
+		t.Fatal(err)
+	}
+
+	img, err := png.Decode(&out)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l := chart.layout()
+	if len(l.legend) != 3 || len(l.note) != 2 {
+		t.Fatalf("Expected 3 legend entries and a note of 2 lines but got %v and %q", l.legend, l.note)
+	}
+	if img.Bounds().Dy() != l.height {
+		t.Fatalf("Expected the image to be %d high to fit the legend and note but got %v", l.height, img.Bounds())
+	}
+
+	if got := img.At(l.plotLeft+10, l.rowTop(1)+rowHeight/2); got != hexColour(imageEstimated) {
+		t.Fatalf("Expected the estimated bar to be drawn in %s but got %v", imageEstimated, got)
+	}
+	// a gap is an outline, empty in the middle
+	gapTop := l.rowTop(2) + (rowHeight-barThickness)/2
+	if got := img.At(l.plotLeft+gapWidth/2, gapTop); got != hexColour(imageGap) {
+		t.Fatalf("Expected the gap's outline to be drawn in %s but got %v", imageGap, got)
+	}
+	if got := img.At(l.plotLeft+gapWidth/2, gapTop+barThickness/2); got != hexColour(imageBackground) {
+		t.Fatalf("Expected the gap to be empty inside but got %v", got)
+	}
+}
@@ -0,0 +1,73 @@
+package barchart
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"strconv"
+)
+
+// WritePNG draws the chart as a PNG image laid out the same as WriteSVG
+func (b BarChart) WritePNG(w io.Writer) error {
+	l := b.layout()
+	img := image.NewRGBA(image.Rect(0, 0, l.width, l.height))
+	ink := hexColour(imageInk)
+
+	fill(img, 0, 0, l.width, l.height, hexColour(imageBackground))
+	drawText(img, (l.width-textWidth(b.title))/2, (titleHeight-glyphHeight)/2, b.title, ink)
+
+	for i, bar := range b.bars {
+		top := l.rowTop(i)
+
+		drawText(img, l.plotLeft-charWidth-textWidth(l.labels[i]), top+(rowHeight-glyphHeight)/2, l.labels[i], ink)
+		if bar.gap {
+			outline(img, l.plotLeft, top+(rowHeight-barThickness)/2, gapWidth, barThickness, hexColour(imageGap))
+		} else {
+			fill(img, l.plotLeft, top+(rowHeight-barThickness)/2, l.barLength(bar.value), barThickness, hexColour(imageFill(bar)))
+		}
+	}
+
+	fill(img, l.plotLeft, l.plotTop, 1, l.plotBottom-l.plotTop, ink)
+	fill(img, l.plotLeft, l.plotBottom, l.plotRight-l.plotLeft+1, 1, ink)
+	for _, tick := range l.ticks {
+		fill(img, tick.x, l.plotBottom, 1, 5, ink)
+		drawText(img, tick.x-textWidth(tick.label)/2, l.plotBottom+10, tick.label, ink)
+	}
+
+	for i, entry := range l.legend {
+		y := l.legendTop(i)
+		if entry.gap {
+			outline(img, imageMargin, y, gapWidth, barThickness, hexColour(entry.colour))
+		} else {
+			fill(img, imageMargin, y, charWidth*2, barThickness, hexColour(entry.colour))
+		}
+		drawText(img, imageMargin+charWidth*3, y+(barThickness-glyphHeight)/2, entry.label, ink)
+	}
+
+	for i, line := range l.note {
+		drawText(img, imageMargin, l.noteTop(i)+(barThickness-glyphHeight)/2, line, ink)
+	}
+
+	return png.Encode(w, img)
+}
+
+// fill colours the width by height rectangle with its top left corner at x, y
+func fill(img *image.RGBA, x int, y int, width int, height int, c color.Color) {
+	draw.Draw(img, image.Rect(x, y, x+width, y+height), &image.Uniform{C: c}, image.Point{}, draw.Src)
+}
+
+// outline draws the edges of the width by height rectangle with its top left corner at x, y
+func outline(img *image.RGBA, x int, y int, width int, height int, c color.Color) {
+	fill(img, x, y, width, 1, c)
+	fill(img, x, y+height-1, width, 1, c)
+	fill(img, x, y, 1, height, c)
+	fill(img, x+width-1, y, 1, height, c)
+}
+
+// hexColour turns a colour like #4477aa into one the image package can draw with
+func hexColour(hex string) color.RGBA {
+	rgb, _ := strconv.ParseUint(hex[1:], 16, 32)
+	return color.RGBA{R: uint8(rgb >> 16), G: uint8(rgb >> 8), B: uint8(rgb), A: 0xff}
+}
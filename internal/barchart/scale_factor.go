@@ -1,18 +1,22 @@
 package barchart
 
+// CalculateScaleFactor works out what to multiply the values by so the biggest bar is desiredValue long
 func CalculateScaleFactor(bars []Bar, desiredValue float64) float64 {
-	highestValue := 0.0
-	for _, bar := range bars {
-		if float64(bar.Count()) > highestValue {
-			highestValue = float64(bar.Count())
-		}
+	highestValue := highestValue(bars)
+	if highestValue <= 0 {
+		return 1.0
 	}
 
-	scaleFactor := 1.0
+	return desiredValue / highestValue
+}
 
-	for highestValue * scaleFactor > desiredValue {
-		scaleFactor = scaleFactor / 2.0
+// availableWidth is how much of a line of the given width is left for the bars once the y-axis
+// labels and the two spaces of padding after the longest bar are taken off. There's always room
+// for at least one character
+func availableWidth(width int, yAxisWidth int) float64 {
+	available := width - yAxisWidth - 2
+	if available < 1 {
+		available = 1
 	}
-
-	return scaleFactor
+	return float64(available)
 }
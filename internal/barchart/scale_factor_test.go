@@ -11,8 +11,8 @@ func TestCalculateScaleFactorForOneBarWithDesiredValueGreaterThanCount(t *testin
 
 	scaleFactor := CalculateScaleFactor(bars, 100.0)
 
-	if scaleFactor != 1.0 {
-		t.Fatalf("scaleFactor should be 1.0 but was %f", scaleFactor)
+	if scaleFactor != 10.0 {
+		t.Fatalf("scaleFactor should be 10.0 but was %f", scaleFactor)
 	}
 }
 
@@ -35,8 +35,8 @@ func TestCalculateScaleFactorForOneBarWithDesiredValueLowerThanCount(t *testing.
 
 	scaleFactor := CalculateScaleFactor(bars, 10.0)
 
-	if scaleFactor != 0.0625 {
-		t.Fatalf("scaleFactor should be 0.0625 but was %f", scaleFactor)
+	if scaleFactor != 0.1 {
+		t.Fatalf("scaleFactor should be 0.1 but was %f", scaleFactor)
 	}
 }
 
@@ -48,8 +48,8 @@ func TestCalculateScaleFactorForMultipleBarsWithDesiredValueGreaterThanHighestCo
 
 	scaleFactor := CalculateScaleFactor(bars, 1000.0)
 
-	if scaleFactor != 1.0 {
-		t.Fatalf("scaleFactor should be 1.0 but was %f", scaleFactor)
+	if scaleFactor != 1000.0/300.0 {
+		t.Fatalf("scaleFactor should be 3.333333 but was %f", scaleFactor)
 	}
 }
 
@@ -74,7 +74,30 @@ func TestCalculateScaleFactorForMultipleBarsWithDesiredValueLessThanHighestCount
 
 	scaleFactor := CalculateScaleFactor(bars, 10.0)
 
-	if scaleFactor != 0.03125 {
+	if scaleFactor != 10.0/300.0 {
+		t.Fatalf("scaleFactor should be 0.033333 but was %f", scaleFactor)
+	}
+}
+
+func TestCalculateScaleFactorForFractionalValues(t *testing.T) {
+	bars := make([]Bar, 0)
+	bars = append(bars, NewBar("1st Jan", 0.25))
+	bars = append(bars, NewBar("2nd Jan", 12.5))
+
+	scaleFactor := CalculateScaleFactor(bars, 10.0)
+
+	if scaleFactor != 0.8 {
+		t.Fatalf("scaleFactor should be 0.8 but was %f", scaleFactor)
+	}
+}
+
+func TestCalculateScaleFactorWhenAllValuesAreZero(t *testing.T) {
+	bars := make([]Bar, 0)
+	bars = append(bars, NewBar("1st Jan", 0))
+
+	scaleFactor := CalculateScaleFactor(bars, 10.0)
+
+	if scaleFactor != 1.0 {
 		t.Fatalf("scaleFactor should be 1.0 but was %f", scaleFactor)
 	}
 }
@@ -0,0 +1,167 @@
+package barchart
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// the levels a sparkline is drawn with, lowest first
+var (
+	unicodeLevels = []string{"▁", "▂", "▃", "▄", "▅", "▆", "▇", "█"}
+	asciiLevels   = []string{"_", ".", ",", "-", "~", "=", "+", "#"}
+)
+
+// Sparklines draws each series as one line of characters whose height follows the values, so the
+// trends in several areas fit on one screen. Each line is scaled to its own peak so the shape can
+// be compared, with the latest and peak figures after it for the size
+type Sparklines struct {
+	title   string
+	series  []Series
+	format  Formatter
+	unicode bool
+	colour  bool
+	note    string
+}
+
+func NewSparklines(title string, series []Series) (Sparklines, error) {
+	if len(series) < 1 {
+		return Sparklines{}, errors.New("there are no series in the sparklines")
+	}
+
+	for _, s := range series {
+		if len(s.bars) < 1 {
+			return Sparklines{}, fmt.Errorf("there are no bars in the '%s' series", s.name)
+		}
+		if len(s.bars) != len(series[0].bars) {
+			return Sparklines{}, fmt.Errorf("the '%s' series has %d bars but '%s' has %d",
+				s.name, len(s.bars), series[0].name, len(series[0].bars))
+		}
+	}
+
+	return Sparklines{title: title, series: series, format: IntegerFormat}, nil
+}
+
+// WithFormatter returns a copy of the sparklines that show the figures with the given formatter
+func (s Sparklines) WithFormatter(format Formatter) Sparklines {
+	s.format = format
+	return s
+}
+
+// WithUnicode returns a copy of the sparklines drawn with unicode blocks rather than punctuation
+func (s Sparklines) WithUnicode() Sparklines {
+	s.unicode = true
+	return s
+}
+
+// WithColour returns a copy of the sparklines that draws each series in its own colour
+func (s Sparklines) WithColour() Sparklines {
+	s.colour = true
+	return s
+}
+
+// WithNote returns a copy of the sparklines with a note under them, e.g. saying how gaps were filled
+func (s Sparklines) WithNote(note string) Sparklines {
+	s.note = note
+	return s
+}
+
+// PlotToWidth draws a line per series that fits in width characters. When there are more bars
+// than fit, neighbouring bars are averaged into one character
+func (s Sparklines) PlotToWidth(width int) string {
+	var plotted string
+
+	nameWidth := 0
+	for _, series := range s.series {
+		if len(series.name) > nameWidth {
+			nameWidth = len(series.name)
+		}
+	}
+
+	figures := make([]string, len(s.series))
+	for i, series := range s.series {
+		bars := series.bars
+		latest := s.format(bars[len(bars)-1].value)
+		if bars[len(bars)-1].gap {
+			latest = gapLabel
+		}
+		figures[i] = fmt.Sprintf("latest %s, peak %s", latest, s.format(highestValue(bars)))
+	}
+	figureWidth := 0
+	for _, figure := range figures {
+		if len(figure) > figureWidth {
+			figureWidth = len(figure)
+		}
+	}
+
+	columns := int(availableWidth(width, nameWidth+len(" ")+len("  ")+figureWidth))
+
+	plotted += "\n"
+	plotted += "----- " + s.title + " -----\n"
+	plotted += "\n"
+
+	bucketSize := 1
+	hasIncomplete := false
+	var labels []Bar
+	for i, series := range s.series {
+		var bars []Bar
+		bars, bucketSize = bucket(series.bars, columns)
+		labels = bars
+
+		plotted += series.name + strings.Repeat(" ", nameWidth-len(series.name)) + " "
+		line := s.line(bars)
+		if s.colour {
+			line = paint(line, seriesColours[i])
+		}
+		plotted += line + "  " + figures[i] + "\n"
+
+		for _, bar := range bars {
+			hasIncomplete = hasIncomplete || bar.incomplete
+		}
+	}
+
+	// the first and last labels under the start and end of the lines
+	first, last := labels[0].label, labels[len(labels)-1].label
+	gap := len(labels) - len(first) - len(last)
+	if gap < 1 {
+		gap = 1
+	}
+	plotted += strings.Repeat(" ", nameWidth+len(" ")) + first + strings.Repeat(" ", gap) + last + "\n"
+	plotted += "\n"
+
+	if bucketSize > 1 {
+		plotted += fmt.Sprintf("Each character is the average of %d values\n", bucketSize)
+		plotted += "\n"
+	}
+
+	if hasIncomplete {
+		plotted += "The most recent figures are still being reported, so are likely to rise\n"
+		plotted += "\n"
+	}
+
+	if s.note != "" {
+		plotted += s.note + "\n"
+		plotted += "\n"
+	}
+
+	return plotted
+}
+
+func (s Sparklines) line(bars []Bar) string {
+	levels := asciiLevels
+	if s.unicode {
+		levels = unicodeLevels
+	}
+
+	scaleFactor := CalculateScaleFactor(bars, float64(len(levels)-1))
+
+	var line string
+	for _, bar := range bars {
+		if bar.gap {
+			line += " "
+			continue
+		}
+		line += levels[scaledLength(bar.value, scaleFactor)]
+	}
+	return line
+}
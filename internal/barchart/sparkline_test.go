@@ -0,0 +1,71 @@
+package barchart
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewSparklinesThrowsErrorIfThereAreNoSeries(t *testing.T) {
+	_, err := NewSparklines("title", make([]Series, 0))
+
+	if err == nil {
+		t.Fatalf("NewSparklines() should throw an error if there are no series")
+	}
+}
+
+func TestSparklinesPlotsALinePerSeries(t *testing.T) {
+	series := []Series{
+		NewSeries("London", []Bar{NewBar("1st", 0), NewBar("2nd", 7), NewBar("3rd", 14)}),
+		NewSeries("Leeds", []Bar{NewBar("1st", 8), NewBar("2nd", 4), NewBar("3rd", 2).MarkIncomplete()}),
+	}
+
+	chart, err := NewSparklines("Cases", series)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e := "\n----- Cases -----\n\n" +
+		"London _-#  latest 14, peak 14\n" +
+		"Leeds  #-.  latest 2, peak 8\n" +
+		"       1st 3rd\n\n" +
+		"The most recent figures are still being reported, so are likely to rise\n\n"
+
+	plotted := chart.PlotToWidth(80)
+
+	if plotted != e {
+		t.Fatalf("Expected: %s\n\n Got: %s\n\n", e, plotted)
+	}
+}
+
+func TestSparklinesWithUnicodeUseBlocks(t *testing.T) {
+	var bars []Bar
+	for i := 0; i < 8; i++ {
+		bars = append(bars, NewBar("d", float64(i)))
+	}
+
+	chart, err := NewSparklines("Cases", []Series{NewSeries("England", bars)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plotted := chart.WithUnicode().PlotToWidth(80)
+
+	if !strings.Contains(plotted, "England ▁▂▃▄▅▆▇█  latest 7, peak 7\n") {
+		t.Fatalf("Expected a line of blocks, got %s", plotted)
+	}
+}
+
+func TestSparklinesShowALatestGapAsMissing(t *testing.T) {
+	bars := []Bar{NewBar("1st", 3), NewBar("2nd", 5), NewBar("3rd", 0).MarkGap()}
+
+	chart, err := NewSparklines("Cases", []Series{NewSeries("Leeds", bars)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plotted := chart.PlotToWidth(80)
+
+	if !strings.Contains(plotted, "latest ?, peak 5\n") {
+		t.Fatalf("Expected the latest figure to be missing, got %s", plotted)
+	}
+}
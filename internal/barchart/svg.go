@@ -0,0 +1,86 @@
+package barchart
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strings"
+)
+
+// WriteSVG draws the chart as an SVG image, with the same title, labels and values as Plot and an
+// x-axis along the bottom
+func (b BarChart) WriteSVG(w io.Writer) error {
+	l := b.layout()
+	var svg strings.Builder
+
+	fmt.Fprintf(&svg, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" `+
+		`font-family="monospace" font-size="13">`+"\n", l.width, l.height, l.width, l.height)
+	fmt.Fprintf(&svg, `  <rect width="%d" height="%d" fill="%s"/>`+"\n", l.width, l.height, imageBackground)
+	fmt.Fprintf(&svg, `  <text x="%d" y="%d" text-anchor="middle" font-size="16" font-weight="bold" fill="%s">%s</text>`+"\n",
+		l.width/2, titleHeight*2/3, imageInk, html.EscapeString(b.title))
+
+	for i, bar := range b.bars {
+		top := l.rowTop(i)
+
+		fmt.Fprintf(&svg, `  <text x="%d" y="%d" text-anchor="end" fill="%s">%s</text>`+"\n",
+			l.plotLeft-charWidth, top+rowHeight/2+4, imageInk, html.EscapeString(l.labels[i]))
+		if bar.gap {
+			writeSVGGap(&svg, l.plotLeft, top+(rowHeight-barThickness)/2)
+		} else if length := l.barLength(bar.value); length > 0 {
+			fmt.Fprintf(&svg, `  <rect x="%d" y="%d" width="%d" height="%d" fill="%s"/>`+"\n",
+				l.plotLeft, top+(rowHeight-barThickness)/2, length, barThickness, imageFill(bar))
+		}
+	}
+
+	// the axes, with a tick and a label at even steps along the bottom
+	fmt.Fprintf(&svg, `  <line x1="%d" y1="%d" x2="%d" y2="%d" stroke="%s"/>`+"\n",
+		l.plotLeft, l.plotTop, l.plotLeft, l.plotBottom, imageInk)
+	fmt.Fprintf(&svg, `  <line x1="%d" y1="%d" x2="%d" y2="%d" stroke="%s"/>`+"\n",
+		l.plotLeft, l.plotBottom, l.plotRight, l.plotBottom, imageInk)
+	for _, tick := range l.ticks {
+		fmt.Fprintf(&svg, `  <line x1="%d" y1="%d" x2="%d" y2="%d" stroke="%s"/>`+"\n",
+			tick.x, l.plotBottom, tick.x, l.plotBottom+4, imageInk)
+		fmt.Fprintf(&svg, `  <text x="%d" y="%d" text-anchor="middle" fill="%s">%s</text>`+"\n",
+			tick.x, l.plotBottom+20, imageInk, html.EscapeString(tick.label))
+	}
+
+	for i, entry := range l.legend {
+		y := l.legendTop(i)
+		if entry.gap {
+			writeSVGGap(&svg, imageMargin, y)
+		} else {
+			fmt.Fprintf(&svg, `  <rect x="%d" y="%d" width="%d" height="%d" fill="%s"/>`+"\n",
+				imageMargin, y, charWidth*2, barThickness, entry.colour)
+		}
+		fmt.Fprintf(&svg, `  <text x="%d" y="%d" fill="%s">%s</text>`+"\n",
+			imageMargin+charWidth*3, y+barThickness-3, imageInk, html.EscapeString(entry.label))
+	}
+
+	for i, line := range l.note {
+		fmt.Fprintf(&svg, `  <text x="%d" y="%d" fill="%s">%s</text>`+"\n",
+			imageMargin, l.noteTop(i)+barThickness-3, imageInk, html.EscapeString(line))
+	}
+
+	svg.WriteString("</svg>\n")
+
+	_, err := io.WriteString(w, svg.String())
+	return err
+}
+
+// writeSVGGap draws the dashed outline that stands in for a bar with no value
+func writeSVGGap(svg *strings.Builder, x int, y int) {
+	fmt.Fprintf(svg, `  <rect x="%d" y="%d" width="%d" height="%d" fill="none" stroke="%s" stroke-dasharray="3,2"/>`+"\n",
+		x, y, gapWidth, barThickness-1, imageGap)
+}
+
+// imageFill is the colour of the bar, lighter when it's incomplete and a different colour when it's
+// estimated, like the markers of the text charts
+func imageFill(bar Bar) string {
+	if bar.estimated {
+		return imageEstimated
+	}
+	if bar.incomplete {
+		return imageIncomplete
+	}
+	return imageBar
+}
@@ -0,0 +1,171 @@
+package coviddata
+
+import (
+	"covid-stats-cli/internal/barchart"
+	"fmt"
+	"math"
+	"time"
+)
+
+// Summary describes how a metric is trending over the last couple of weeks
+type Summary struct {
+	Title    string
+	ThisWeek float64
+	LastWeek float64
+	// Averaged is true when the weekly figures are daily averages rather than totals
+	Averaged bool
+	// PercentChange and the doubling/halving times are only meaningful when HasChange is true,
+	// i.e. both weeks had something to compare
+	HasChange     bool
+	PercentChange float64
+	// DoublingDays is positive when the weekly total is growing and HalvingDays is positive when
+	// it's shrinking. Both are 0 when the totals are flat
+	DoublingDays float64
+	HalvingDays  float64
+	PeakDate     time.Time
+	PeakValue    float64
+	// CompleteTo is the last day of this week when the days since are still being reported, so are
+	// left out of the weekly figures and peak. It's zero when every day's complete
+	CompleteTo time.Time
+	// format shows the figures the way the metric's charted, e.g. as a percentage
+	format barchart.Formatter
+}
+
+// summaryDays is how many days a summary needs, as the weekly totals compare the last fortnight, even
+// when charting less than that
+const summaryDays = 14
+
+// summaryDays is how many days the metric's summary needs, which is the fortnight before the days
+// still being reported when it's charted by event date
+func (m Metric) summaryDays() int {
+	return summaryDays + m.incompleteDays
+}
+
+// summarise works out the weekly figures from the 14 days before now and the peak over the charted
+// window. The weekly figures are totals, or daily averages for level metrics, which include rates
+// like the positivity rate. The days still being reported would look like a fall, so the fortnight
+// ends before them instead, comparing complete weeks. covidData must be sorted oldest -> newest
+func summarise(title string, covidData []data, previousDays int, now time.Time, metric Metric) Summary {
+	summary := Summary{Title: title, format: metric.formatter()}
+	daysThisWeek, daysLastWeek := 0, 0
+
+	end := now.Add(time.Duration(-metric.incompleteDays*24) * time.Hour)
+	if metric.incompleteDays > 0 {
+		summary.CompleteTo = end.Add(-24 * time.Hour)
+	}
+	oneWeekAgo := end.Add(-7 * 24 * time.Hour)
+	twoWeeksAgo := end.Add(-14 * 24 * time.Hour)
+	windowStart := now.Add(time.Duration(-previousDays*24) * time.Hour)
+
+	for _, d := range covidData {
+		if metric.isIncomplete(d, now) {
+			continue
+		}
+
+		value, _ := d.value(metric)
+		if isOnOrAfter(oneWeekAgo, d.date) {
+			summary.ThisWeek += value
+			daysThisWeek++
+		} else if isOnOrAfter(twoWeeksAgo, d.date) {
+			summary.LastWeek += value
+			daysLastWeek++
+		}
+
+		if isOnOrAfter(windowStart, d.date) && (summary.PeakDate.IsZero() || value > summary.PeakValue) {
+			summary.PeakDate = d.date
+			summary.PeakValue = value
+		}
+	}
+
+	if metric.level {
+		summary.Averaged = true
+		summary.ThisWeek = averageOver(summary.ThisWeek, daysThisWeek)
+		summary.LastWeek = averageOver(summary.LastWeek, daysLastWeek)
+	}
+
+	if summary.ThisWeek > 0 && summary.LastWeek > 0 {
+		summary.HasChange = true
+		summary.PercentChange = (summary.ThisWeek - summary.LastWeek) / summary.LastWeek * 100
+
+		// assumes exponential growth across the fortnight: total(t) = total(0) * e^(rt)
+		weeklyGrowth := math.Log(summary.ThisWeek / summary.LastWeek)
+		if weeklyGrowth > 0 {
+			summary.DoublingDays = 7 * math.Ln2 / weeklyGrowth
+		} else if weeklyGrowth < 0 {
+			summary.HalvingDays = 7 * math.Ln2 / -weeklyGrowth
+		}
+	}
+
+	return summary
+}
+
+func averageOver(total float64, days int) float64 {
+	if days == 0 {
+		return 0
+	}
+	return total / float64(days)
+}
+
+// SummaryField is a line of a summary, e.g. Change and +12.5%
+type SummaryField struct {
+	Name  string
+	Value string
+}
+
+// Fields are the summary's figures in the order they're shown
+func (s Summary) Fields() []SummaryField {
+	var fields []SummaryField
+	if !s.CompleteTo.IsZero() {
+		fields = append(fields, SummaryField{"Weeks to", s.CompleteTo.Format("02/01") +
+			", as the days since are still being reported"})
+	}
+
+	if s.Averaged {
+		fields = append(fields, SummaryField{"This week", s.formatted(s.ThisWeek) + " a day on average"})
+		fields = append(fields, SummaryField{"Last week", s.formatted(s.LastWeek) + " a day on average"})
+	} else {
+		fields = append(fields, SummaryField{"This week", s.formatted(s.ThisWeek)})
+		fields = append(fields, SummaryField{"Last week", s.formatted(s.LastWeek)})
+	}
+
+	if s.HasChange {
+		fields = append(fields, SummaryField{"Change", fmt.Sprintf("%+.1f%%", s.PercentChange)})
+	} else {
+		fields = append(fields, SummaryField{"Change", "n/a"})
+	}
+
+	if s.DoublingDays > 0 {
+		fields = append(fields, SummaryField{"Doubling time", fmt.Sprintf("%.1f days", s.DoublingDays)})
+	} else if s.HalvingDays > 0 {
+		fields = append(fields, SummaryField{"Halving time", fmt.Sprintf("%.1f days", s.HalvingDays)})
+	}
+
+	if !s.PeakDate.IsZero() {
+		fields = append(fields, SummaryField{"Peak day", fmt.Sprintf("%s (%s)", s.PeakDate.Format("02/01"), s.formatted(s.PeakValue))})
+	}
+
+	return fields
+}
+
+// formatted shows a figure with the summary's formatter, or as a whole number when it has none
+func (s Summary) formatted(value float64) string {
+	if s.format == nil {
+		return barchart.ThousandsFormat(value)
+	}
+	return s.format(value)
+}
+
+func (s Summary) String() string {
+	summary := "----- " + s.Title + " summary -----\n"
+	summary += "\n"
+	for _, field := range s.Fields() {
+		summary += fmt.Sprintf("%-15s%s\n", field.Name+":", field.Value)
+	}
+
+	return summary
+}
+
+// summary is the weekly figures for the fetched covidData, which has to go back the metric's summaryDays
+func (h Handler) summary(q Query, covidData []data) Summary {
+	return summarise(chartTitle(q.Metric.Title, h.api.area()), covidData, q.PreviousDays, time.Now(), q.Metric)
+}
@@ -0,0 +1,174 @@
+package coviddata
+
+import (
+	"context"
+	"covid-stats-cli/internal/logging"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func givenDailyCases(now time.Time, casesByDaysAgo map[int]int) []data {
+	var covidData []data
+	for daysAgo := 14; daysAgo >= 1; daysAgo-- {
+		if cases, ok := casesByDaysAgo[daysAgo]; ok {
+			covidData = append(covidData, data{date: now.Add(time.Duration(-24*daysAgo) * time.Hour), values: map[string]float64{"cases": float64(cases)}})
+		}
+	}
+	return covidData
+}
+
+func TestSummarise_GrowingCases(t *testing.T) {
+	now := time.Now()
+	casesByDaysAgo := make(map[int]int)
+	for daysAgo := 1; daysAgo <= 14; daysAgo++ {
+		if daysAgo <= 7 {
+			casesByDaysAgo[daysAgo] = 20
+		} else {
+			casesByDaysAgo[daysAgo] = 10
+		}
+	}
+	casesByDaysAgo[3] = 50
+
+	summary := summarise("New cases", givenDailyCases(now, casesByDaysAgo), 7, now, Cases)
+
+	if summary.ThisWeek != 170 || summary.LastWeek != 70 {
+		t.Fatalf("Expected weekly totals of 170 and 70, got %+v", summary)
+	}
+
+	if !summary.HasChange || math.Abs(summary.PercentChange-142.857) > 0.001 {
+		t.Fatalf("Expected a change of +142.857%%, got %+v", summary)
+	}
+
+	expectedDoubling := 7 * math.Ln2 / math.Log(170.0/70.0)
+	if math.Abs(summary.DoublingDays-expectedDoubling) > 0.001 || summary.HalvingDays != 0 {
+		t.Fatalf("Expected a doubling time of %f days, got %+v", expectedDoubling, summary)
+	}
+
+	if summary.PeakValue != 50 || !isSameDay(summary.PeakDate, now.Add(-72*time.Hour)) {
+		t.Fatalf("Expected the peak to be 50 three days ago, got %+v", summary)
+	}
+}
+
+func TestSummarise_ShrinkingCases(t *testing.T) {
+	now := time.Now()
+	summary := summarise("New cases", givenDailyCases(now, map[int]int{2: 25, 9: 100}), 14, now, Cases)
+
+	if summary.PercentChange != -75 || math.Abs(summary.HalvingDays-3.5) > 0.001 || summary.DoublingDays != 0 {
+		t.Fatalf("Expected a 75%% drop halving every 3.5 days, got %+v", summary)
+	}
+
+	if summary.PeakValue != 100 {
+		t.Fatalf("Expected the peak over the fortnight to be 100, got %+v", summary)
+	}
+}
+
+func TestSummarise_NothingLastWeek(t *testing.T) {
+	now := time.Now()
+	summary := summarise("New cases", givenDailyCases(now, map[int]int{2: 25}), 7, now, Cases)
+
+	if summary.HasChange || !strings.Contains(summary.String(), "Change:        n/a") {
+		t.Fatalf("Expected no change to be reported, got %+v", summary)
+	}
+}
+
+func TestSummarise_RateIsAveragedAsAPercentage(t *testing.T) {
+	now := time.Now()
+	var covidData []data
+	for daysAgo := 14; daysAgo >= 1; daysAgo-- {
+		rate := 4.6
+		if daysAgo <= 7 {
+			rate = 5.4
+		}
+		covidData = append(covidData, data{date: now.Add(time.Duration(-24*daysAgo) * time.Hour),
+			values: map[string]float64{"positivity": rate}})
+	}
+
+	summary := summarise("Positivity rate (%)", covidData, 7, now, PositivityRate)
+
+	if math.Abs(summary.ThisWeek-5.4) > 0.001 || math.Abs(summary.LastWeek-4.6) > 0.001 || !summary.Averaged {
+		t.Fatalf("Expected daily averages of 5.4 and 4.6, got %+v", summary)
+	}
+	for _, expected := range []string{"This week:     5.4% a day on average\n", "Last week:     4.6% a day on average\n",
+		"Change:        +17.4%\n", "(5.4%)\n"} {
+		if !strings.Contains(summary.String(), expected) {
+			t.Errorf("Expected the summary to contain '%s', got %s", expected, summary)
+		}
+	}
+}
+
+func TestSummarise_ByEventDateComparesCompleteWeeks(t *testing.T) {
+	now := time.Now()
+	var covidData []data
+	for daysAgo := 19; daysAgo >= 1; daysAgo-- {
+		deaths := 10
+		if daysAgo <= 5 {
+			deaths = 1 // still being reported
+		} else if daysAgo <= 12 {
+			deaths = 20
+		}
+		covidData = append(covidData, data{date: now.Add(time.Duration(-24*daysAgo) * time.Hour),
+			values: map[string]float64{"deaths": float64(deaths)}})
+	}
+
+	summary := summarise("New deaths", covidData, 7, now, Deaths.ByDate(EventDate))
+
+	if summary.ThisWeek != 140 || summary.LastWeek != 70 || summary.DoublingDays != 7 {
+		t.Fatalf("Expected the complete weeks of 140 and 70 doubling in 7 days, got %+v", summary)
+	}
+	if !isSameDay(summary.CompleteTo, now.Add(-6*24*time.Hour)) || summary.PeakValue != 20 {
+		t.Fatalf("Expected the weeks to end 6 days ago at a peak of 20, got %+v", summary)
+	}
+	if !strings.Contains(summary.String(), "as the days since are still being reported") {
+		t.Errorf("Expected the summary to say the latest days are left out, got %s", summary)
+	}
+}
+
+func TestHandler_Run_DeathsSummary_FetchesAtLeastAFortnight(t *testing.T) {
+	requestedDays := 0
+	mockApi := mockRestApi{mockGetData: func(_ Area, previousDays int) ([]data, error) {
+		requestedDays = previousDays
+		return []data{{date: time.Now().Add(-24 * time.Hour), values: map[string]float64{"deaths": 3}}}, nil
+	}}
+	handler := NewHandler(mockApi, logging.Discard())
+
+	result, err := handler.Run(context.Background(), Query{Metric: Deaths, PreviousDays: 7,
+		Render: []Renderer{RenderSummary}})
+	summary := result.Summary
+
+	if err != nil || requestedDays != 14 || summary.ThisWeek != 3 {
+		t.Fatalf("Expected 14 days to be fetched and 3 deaths this week, got %d days, %+v and err %v",
+			requestedDays, summary, err)
+	}
+}
+
+func TestHandler_Run_SummaryByEventDate_FetchesAFortnightOfCompleteDays(t *testing.T) {
+	requestedDays := 0
+	mockApi := mockRestApi{mockGetData: func(_ Area, previousDays int) ([]data, error) {
+		requestedDays = previousDays
+		return []data{{date: time.Now().Add(-24 * time.Hour), values: map[string]float64{"cases": 3}}}, nil
+	}}
+	handler := NewHandler(mockApi, logging.Discard())
+
+	_, err := handler.Run(context.Background(), Query{Metric: Cases, PreviousDays: 7, DateBasis: EventDate,
+		Render: []Renderer{RenderSummary}})
+
+	if err != nil || requestedDays != 19 {
+		t.Fatalf("Expected the 14 complete days and the 5 still being reported to be fetched, got %d days and err %v",
+			requestedDays, err)
+	}
+}
+
+func TestHandler_Run_CasesSummary_ApiReturnsError(t *testing.T) {
+	apiErr := errors.New("our data centre went bye bye")
+	handler := NewHandler(givenApiThatReturns(nil, apiErr), logging.Discard())
+
+	_, err := handler.Run(context.Background(), Query{Metric: Cases, PreviousDays: 7,
+		Render: []Renderer{RenderSummary}})
+
+	if err != apiErr {
+		t.Fatalf("Expected error %v to equal %v", err, apiErr)
+	}
+}
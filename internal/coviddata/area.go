@@ -0,0 +1,94 @@
+package coviddata
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+type AreaType string
+
+const (
+	Overview  AreaType = "overview"
+	Nation    AreaType = "nation"
+	Region    AreaType = "region"
+	NhsRegion AreaType = "nhsRegion"
+	Utla      AreaType = "utla"
+	Ltla      AreaType = "ltla"
+)
+
+// the order the area types are offered in the menu
+var AreaTypes = []AreaType{Overview, Nation, Region, NhsRegion, Utla, Ltla}
+
+// the dashboard api only has a fixed set of names for the broader area types. utlas and ltlas
+// are too numerous to list, so any name is passed through and left to the api to resolve
+var knownAreaNames = map[AreaType][]string{
+	Overview: {"United Kingdom"},
+	Nation:   {"England", "Northern Ireland", "Scotland", "Wales"},
+	Region: {"East Midlands", "East of England", "London", "North East", "North West", "South East",
+		"South West", "West Midlands", "Yorkshire and The Humber"},
+	NhsRegion: {"East of England", "London", "Midlands", "North East and Yorkshire", "North West",
+		"South East", "South West"},
+}
+
+var England = Area{Type: Nation, Name: "England"}
+
+type Area struct {
+	Type AreaType
+	Name string
+}
+
+func NewArea(areaType string, name string) (Area, error) {
+	t, err := parseAreaType(areaType)
+	if err != nil {
+		return Area{}, err
+	}
+
+	name = strings.TrimSpace(name)
+	if t == Overview && name == "" {
+		name = knownAreaNames[Overview][0]
+	}
+	if name == "" {
+		return Area{}, fmt.Errorf("an area name is required for area type '%s'", t)
+	}
+
+	names, ok := knownAreaNames[t]
+	if !ok {
+		return Area{t, name}, nil
+	}
+
+	for _, known := range names {
+		if strings.EqualFold(known, name) {
+			return Area{t, known}, nil
+		}
+	}
+
+	return Area{}, fmt.Errorf("'%s' isn't a valid %s, choose one of: %s", name, t, strings.Join(names, ", "))
+}
+
+func parseAreaType(areaType string) (AreaType, error) {
+	for _, t := range AreaTypes {
+		if strings.EqualFold(string(t), strings.TrimSpace(areaType)) {
+			return t, nil
+		}
+	}
+
+	var types []string
+	for _, t := range AreaTypes {
+		types = append(types, string(t))
+	}
+	return "", fmt.Errorf("'%s' isn't a valid area type, choose one of: %s", areaType, strings.Join(types, ", "))
+}
+
+func (a Area) String() string {
+	return a.Name
+}
+
+// the filter the dashboard api expects, e.g. areaType=nation;areaName=england
+func (a Area) filter() string {
+	if a.Type == Overview {
+		return "areaType=" + string(a.Type)
+	}
+
+	return "areaType=" + string(a.Type) + ";areaName=" + url.PathEscape(strings.ToLower(a.Name))
+}
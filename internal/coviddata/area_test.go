@@ -0,0 +1,63 @@
+package coviddata
+
+import (
+	"testing"
+)
+
+func TestNewArea_NormalisesKnownNames(t *testing.T) {
+	area, err := NewArea("NATION", "northern ireland")
+
+	if err != nil || area != (Area{Nation, "Northern Ireland"}) {
+		t.Fatalf("Expected Northern Ireland nation but got %+v and err %v", area, err)
+	}
+}
+
+func TestNewArea_RejectsUnknownAreaType(t *testing.T) {
+	_, err := NewArea("county", "Kent")
+
+	if err == nil {
+		t.Fatalf("NewArea() should return an error for an unknown area type")
+	}
+}
+
+func TestNewArea_RejectsNameNotInAreaType(t *testing.T) {
+	_, err := NewArea("nation", "London")
+
+	if err == nil {
+		t.Fatalf("NewArea() should return an error when London is given as a nation")
+	}
+}
+
+func TestNewArea_RequiresNameForLocalAuthorities(t *testing.T) {
+	_, err := NewArea("ltla", " ")
+
+	if err == nil {
+		t.Fatalf("NewArea() should return an error when an ltla has no name")
+	}
+}
+
+func TestNewArea_PassesThroughLocalAuthorityNames(t *testing.T) {
+	area, err := NewArea("utla", "Manchester")
+
+	if err != nil || area != (Area{Utla, "Manchester"}) {
+		t.Fatalf("Expected Manchester utla but got %+v and err %v", area, err)
+	}
+}
+
+func TestNewArea_OverviewDefaultsToUnitedKingdom(t *testing.T) {
+	area, err := NewArea("overview", "")
+
+	if err != nil || area != (Area{Overview, "United Kingdom"}) {
+		t.Fatalf("Expected United Kingdom overview but got %+v and err %v", area, err)
+	}
+}
+
+func TestArea_Filter(t *testing.T) {
+	if f := (Area{Nation, "Northern Ireland"}).filter(); f != "areaType=nation;areaName=northern%20ireland" {
+		t.Fatalf("unexpected filter %s", f)
+	}
+
+	if f := (Area{Overview, "United Kingdom"}).filter(); f != "areaType=overview" {
+		t.Fatalf("unexpected filter %s", f)
+	}
+}
@@ -0,0 +1,39 @@
+package coviddata
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ChartStyle is how a single area's chart is drawn
+type ChartStyle string
+
+const (
+	// Horizontal draws a row per day with its figure next to the date
+	Horizontal ChartStyle = "horizontal"
+	// Blocks draws a row per day like Horizontal, but with unicode blocks that show eighths of a
+	// character
+	Blocks ChartStyle = "blocks"
+	// Vertical draws a column per day, which fits many more weeks on the screen
+	Vertical ChartStyle = "vertical"
+	// Sparkline draws a single line per area, so the trends in several areas fit on one screen
+	Sparkline ChartStyle = "sparkline"
+)
+
+// ChartStyles are the styles in the order the menu cycles through them
+var ChartStyles = []ChartStyle{Horizontal, Blocks, Vertical, Sparkline}
+
+func ParseChartStyle(style string) (ChartStyle, error) {
+	switch strings.ToLower(strings.TrimSpace(style)) {
+	case "horizontal", "rows":
+		return Horizontal, nil
+	case "blocks":
+		return Blocks, nil
+	case "vertical", "columns":
+		return Vertical, nil
+	case "sparkline", "sparklines":
+		return Sparkline, nil
+	}
+
+	return "", fmt.Errorf("'%s' isn't a chart style, choose one of: horizontal, blocks, vertical, sparkline", style)
+}
@@ -0,0 +1,19 @@
+package coviddata
+
+import "testing"
+
+func TestParseChartStyle(t *testing.T) {
+	style, err := ParseChartStyle("Columns")
+
+	if err != nil || style != Vertical {
+		t.Fatalf("Expected columns to mean %s but got %s and err %v", Vertical, style, err)
+	}
+}
+
+func TestParseChartStyle_Unknown(t *testing.T) {
+	_, err := ParseChartStyle("pie")
+
+	if err == nil {
+		t.Fatalf("ParseChartStyle() should return an error for a style it doesn't know")
+	}
+}
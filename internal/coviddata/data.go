@@ -3,7 +3,18 @@ package coviddata
 import "time"
 
 type data struct {
-	date   time.Time
-	cases  int
-	deaths int
+	date time.Time
+	// the ons code of the area the figures are for, e.g. E92000001
+	areaCode string
+	// keyed by Metric.Name. a metric the api has no figure for on the day is left out, not set to 0
+	values map[string]float64
+	// a gap is a day the api had no figure for, added so it can be charted as missing
+	gap bool
+	// an estimated day's figure was filled in for a gap rather than published
+	estimated bool
+}
+
+func (d data) value(metric Metric) (float64, bool) {
+	value, ok := d.values[metric.Name]
+	return value, ok
 }
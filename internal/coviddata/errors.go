@@ -0,0 +1,52 @@
+package coviddata
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ErrEmptyResponse is the api having no figures for the area, metric or days asked for
+var ErrEmptyResponse = errors.New("there's no data")
+
+// ErrMissingDate is the api sending figures without saying which day they're for
+var ErrMissingDate = errors.New("the covid data api is returning entries with no specified date")
+
+// QueryError is a query that can't be run whatever the api sends, e.g. a summary comparing areas.
+// It's found before anything's fetched
+type QueryError struct {
+	Reason string
+}
+
+func (e *QueryError) Error() string {
+	return e.Reason
+}
+
+// StatusError is the api answering with a status the figures can't be read from, e.g. 500
+type StatusError struct {
+	StatusCode int
+	Url        string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("the covid data api answered %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// Temporary is true when trying again later could work, i.e. the api's rate limiting or its own error
+func (e *StatusError) Temporary() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}
+
+// DecodeError is a response that isn't what the api should send, e.g. json that doesn't parse
+type DecodeError struct {
+	Url string
+	Err error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("couldn't read the covid data api's response: %v", e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,169 @@
+package coviddata
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// GapPolicy is what's charted for a day the api has no figure for, either because it skipped the
+// day or sent it without the metric
+type GapPolicy string
+
+const (
+	// GapMark charts the day with no value, so it's clear the figure's missing
+	GapMark GapPolicy = "mark"
+	// GapCarryForward charts the figure from the day before, or the day after at the start of the
+	// window, marked as an estimate
+	GapCarryForward GapPolicy = "carry"
+	// GapInterpolate charts a figure on the straight line between the days either side, or the
+	// nearest day's figure at the ends of the window, marked as an estimate
+	GapInterpolate GapPolicy = "interpolate"
+	// GapDrop leaves the day out of the chart
+	GapDrop GapPolicy = "drop"
+)
+
+// GapPolicies are the policies in the order the menu cycles through them
+var GapPolicies = []GapPolicy{GapMark, GapCarryForward, GapInterpolate, GapDrop}
+
+func ParseGapPolicy(policy string) (GapPolicy, error) {
+	switch strings.ToLower(strings.TrimSpace(policy)) {
+	case "mark", "empty":
+		return GapMark, nil
+	case "carry", "carry-forward":
+		return GapCarryForward, nil
+	case "interpolate", "linear":
+		return GapInterpolate, nil
+	case "drop":
+		return GapDrop, nil
+	}
+
+	return "", fmt.Errorf("'%s' isn't a gap policy, choose one of: mark, carry, interpolate, drop", policy)
+}
+
+// SetGapPolicy chooses what's charted for the days the api has no figure for
+func (h *Handler) SetGapPolicy(policy GapPolicy) {
+	h.gapPolicy = policy
+}
+
+func (h Handler) GapPolicy() GapPolicy {
+	if h.gapPolicy == "" {
+		return GapMark
+	}
+	return h.gapPolicy
+}
+
+// fillGaps adds a day for each one covidData's missing in the query's window, from its previous days
+// and the extra days for the rolling average before today through yesterday, as the gap policy says.
+// That includes the days at either end of it. It returns how many of them are charted. covidData
+// must be sorted oldest -> newest
+func (h Handler) fillGaps(covidData []data, q Query) ([]data, int) {
+	if len(covidData) == 0 {
+		return covidData, 0
+	}
+
+	// today's figures aren't published until the afternoon, so the window ends yesterday. The api
+	// only sends days in the window, but any outside it are kept rather than lost
+	now := time.Now()
+	first := now.Add(time.Duration(-(q.PreviousDays+q.extraDays())*24) * time.Hour)
+	if covidData[0].date.Before(first) {
+		first = covidData[0].date
+	}
+	last := now.AddDate(0, 0, -1)
+	if end := covidData[len(covidData)-1].date; end.After(last) {
+		last = end
+	}
+
+	var filled, missing []data
+	var before *data
+	gaps, next := 0, 0
+	for date := first; isOnOrAfter(date, last); date = date.AddDate(0, 0, 1) {
+		if next < len(covidData) && isSameDay(covidData[next].date, date) {
+			filled = append(filled, h.fill(missing, before, &covidData[next], q.Metric)...)
+			filled = append(filled, covidData[next])
+			before, missing = &covidData[next], nil
+			next++
+			continue
+		}
+
+		d := data{date: date, areaCode: covidData[0].areaCode, values: map[string]float64{}}
+		if q.isCharted(d) {
+			gaps++
+		}
+		missing = append(missing, d)
+	}
+
+	return append(filled, h.fill(missing, before, nil, q.Metric)...), gaps
+}
+
+// fill fills in the run of missing days between before and after as the gap policy says. before is
+// nil at the start of the window and after is nil at its end, where the estimates have only the
+// figure on the other side to go on
+func (h Handler) fill(missing []data, before *data, after *data, metric Metric) []data {
+	var filled []data
+	for i, d := range missing {
+		switch h.GapPolicy() {
+		case GapMark:
+			d.gap = true
+		case GapCarryForward:
+			from := before
+			if from == nil {
+				from = after
+			}
+			d.values[metric.Name], _ = from.value(metric)
+			d.estimated = true
+		case GapInterpolate:
+			switch {
+			case before == nil:
+				d.values[metric.Name], _ = after.value(metric)
+			case after == nil:
+				d.values[metric.Name], _ = before.value(metric)
+			default:
+				from, _ := before.value(metric)
+				to, _ := after.value(metric)
+				d.values[metric.Name] = from + (to-from)*float64(i+1)/float64(len(missing)+1)
+			}
+			d.estimated = true
+		case GapDrop:
+			continue
+		}
+		filled = append(filled, d)
+	}
+	return filled
+}
+
+// gapNote says how many figures are missing from a chart and what's been done about them, or is
+// empty when none are
+func gapNote(policy GapPolicy, gaps int) string {
+	if gaps == 0 {
+		return ""
+	}
+
+	note := fmt.Sprintf("Gaps: the api is missing %d figure", gaps)
+	if gaps > 1 {
+		note += "s"
+	}
+
+	switch policy {
+	case GapCarryForward:
+		return note + ", estimated by carrying forward the figure before, or back the first one at the start"
+	case GapInterpolate:
+		return note + ", estimated along a straight line between the figures either side, or the nearest one at the ends"
+	case GapDrop:
+		return note + ", left out of the chart"
+	}
+	return note + ", left empty"
+}
+
+// withGaps spreads the values for the days that aren't gaps back out over covidData, with 0 for the gaps
+func withGaps(covidData []data, values []float64) []float64 {
+	spread := make([]float64, len(covidData))
+	next := 0
+	for i, d := range covidData {
+		if !d.gap {
+			spread[i] = values[next]
+			next++
+		}
+	}
+	return spread
+}
@@ -0,0 +1,103 @@
+package coviddata
+
+import (
+	"context"
+	"covid-stats-cli/internal/logging"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseGapPolicy(t *testing.T) {
+	policy, err := ParseGapPolicy("Linear")
+
+	if err != nil || policy != GapInterpolate {
+		t.Fatalf("Expected linear to mean %s but got %s and err %v", GapInterpolate, policy, err)
+	}
+}
+
+func TestParseGapPolicy_Unknown(t *testing.T) {
+	_, err := ParseGapPolicy("guess")
+
+	if err == nil {
+		t.Fatalf("ParseGapPolicy() should return an error for a policy it doesn't know")
+	}
+}
+
+func TestHandler_FillGaps(t *testing.T) {
+	fourDaysAgo := time.Now().AddDate(0, 0, -4)
+	covidData := []data{
+		{date: fourDaysAgo, areaCode: "E92000001", values: map[string]float64{"cases": 10}},
+		{date: fourDaysAgo.AddDate(0, 0, 2), areaCode: "E92000001", values: map[string]float64{"cases": 30}},
+	}
+
+	// the window's five days ago through yesterday, so there's a gap at both ends and one in the middle
+	tests := []struct {
+		policy    GapPolicy
+		values    []float64
+		gap       bool
+		estimated bool
+	}{
+		{GapMark, []float64{0, 10, 0, 30, 0}, true, false},
+		{GapCarryForward, []float64{10, 10, 10, 30, 30}, false, true},
+		{GapInterpolate, []float64{10, 10, 20, 30, 30}, false, true},
+		{GapDrop, []float64{10, 30}, false, false},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.policy), func(t *testing.T) {
+			handler := NewHandler(mockRestApi{}, logging.Discard())
+			handler.SetGapPolicy(test.policy)
+
+			filled, gaps := handler.fillGaps(covidData, Query{Metric: Cases, PreviousDays: 5})
+
+			if gaps != 3 || len(filled) != len(test.values) {
+				t.Fatalf("expected 3 gaps and %d days, got %d gaps and %d days", len(test.values), gaps, len(filled))
+			}
+			for i, d := range filled {
+				value, _ := d.value(Cases)
+				if value != test.values[i] {
+					t.Errorf("expected day %d to be %v, got %v", i, test.values[i], value)
+				}
+				if d.areaCode != "E92000001" {
+					t.Errorf("expected day %d to be for E92000001, got %s", i, d.areaCode)
+				}
+			}
+			if test.policy != GapDrop {
+				for _, i := range []int{0, 2, 4} {
+					if filled[i].gap != test.gap || filled[i].estimated != test.estimated {
+						t.Errorf("expected day %d to be gap=%v estimated=%v, got %+v", i, test.gap, test.estimated, filled[i])
+					}
+				}
+				if !isSameDay(filled[0].date, fourDaysAgo.AddDate(0, 0, -1)) {
+					t.Errorf("expected the first gap to be five days ago, got %s", filled[0].date)
+				}
+			}
+		})
+	}
+}
+
+func TestHandler_Run_Chart_SaysHowGapsWereFilled(t *testing.T) {
+	oneDayAgo := time.Now().Add(time.Hour * -24)
+	threeDaysAgo := time.Now().Add(time.Hour * -72)
+	mockApi := givenApiThatReturns([]data{
+		{date: oneDayAgo, values: map[string]float64{"cases": 4}},
+		{date: threeDaysAgo, values: map[string]float64{"cases": 2}},
+	}, nil)
+	handler := NewHandler(mockApi, logging.Discard())
+	handler.SetGapPolicy(GapInterpolate)
+
+	result, err := handler.Run(context.Background(), Query{Metric: Cases, PreviousDays: 3,
+		Render: []Renderer{RenderChart}})
+	chart := result.Chart
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(chart, "(~3)") {
+		t.Errorf("expected the missing day to be estimated as ~3, got %s", chart)
+	}
+	if !strings.Contains(chart, "Gaps: the api is missing 1 figure, estimated along a straight line between the figures either side, or the nearest one at the ends\n") {
+		t.Errorf("expected the chart to say how the gap was filled, got %s", chart)
+	}
+}
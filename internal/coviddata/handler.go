@@ -1,66 +1,333 @@
 package coviddata
 
 import (
+	"context"
 	"covid-stats-cli/internal/barchart"
+	"covid-stats-cli/internal/logging"
+	"covid-stats-cli/internal/terminal"
+	"fmt"
 	"sort"
+	"strings"
+	"time"
 )
 
 type Handler struct {
-	api restApi
+	api         restApi
+	populations Populations
+	width       int
+	style       ChartStyle
+	unicode     bool
+	colour      bool
+	gapPolicy   GapPolicy
+	log         *logging.Logger
 }
 
-func NewHandler(api restApi) *Handler {
-	return &Handler{api}
+func NewHandler(api restApi, log *logging.Logger) *Handler {
+	return &Handler{api: api, populations: DefaultPopulations(), width: terminal.DefaultWidth, log: log}
 }
 
-func (h Handler) GetCasesChart(previousWeeks int) (string, error) {
-	covidData, err := h.api.getData(previousWeeks * 7)
+// SetPopulations replaces the populations used for the rates, e.g. with overrides added
+func (h *Handler) SetPopulations(populations Populations) {
+	h.populations = populations
+}
+
+// SetWidth sets how many characters wide the charts can be, e.g. the width of the terminal
+func (h *Handler) SetWidth(width int) {
+	h.width = width
+}
+
+// SetChartStyle chooses how the charts are drawn. Comparisons are drawn as rows unless they're
+// sparklines
+func (h *Handler) SetChartStyle(style ChartStyle) {
+	h.style = style
+}
+
+func (h Handler) ChartStyle() ChartStyle {
+	if h.style == "" {
+		return Horizontal
+	}
+	return h.style
+}
+
+// SetUnicode lets the charts use unicode blocks. Without it the block style falls back to rows of *
+// and the sparklines are drawn with punctuation
+func (h *Handler) SetUnicode(on bool) {
+	h.unicode = on
+}
+
+// SetColour colours the charts with ANSI escape codes, e.g. red for days up on the week before
+func (h *Handler) SetColour(on bool) {
+	h.colour = on
+}
+
+// LastUpdated is when the api last updated the figures the handler has fetched, or zero if the
+// api didn't say
+func (h Handler) LastUpdated() time.Time {
+	return h.api.lastUpdated()
+}
+
+// chart draws the fetched covidData for the query in the handler's style
+func (h Handler) chart(q Query, covidData []data) (string, error) {
+	bars, gaps, err := h.bars(q, covidData)
 	if err != nil {
 		return "", err
 	}
 
-	// sort oldest -> newest
-	sort.Slice(covidData, func(i, j int) bool {
-		return covidData[i].date.Before(covidData[j].date)
-	})
+	title := q.title(h.api.area().Name)
+	note := gapNote(h.GapPolicy(), gaps)
 
-	var bars []barchart.Bar
-	for _, d := range covidData {
-		bars = append(bars, barchart.NewBar(d.date.Format("02/01"), d.cases))
+	switch h.ChartStyle() {
+	case Vertical:
+		chart, err := barchart.NewColumnChart(title, bars)
+		if err != nil {
+			return "", err
+		}
+		chart = chart.WithFormatter(q.formatter()).WithNote(note)
+		if h.colour {
+			chart = chart.WithColour()
+		}
+		return chart.PlotToWidth(h.width), nil
+	case Sparkline:
+		return h.plotSparklines(title, []barchart.Series{barchart.NewSeries(h.api.area().Name, bars)}, q, note)
 	}
 
-	chart, err := barchart.NewBarChart("New cases", bars)
+	chart, err := barchart.NewBarChart(title, bars)
 	if err != nil {
 		return "", err
 	}
+	chart = chart.WithFormatter(q.formatter()).WithNote(note)
+	if h.ChartStyle() == Blocks && h.unicode {
+		chart = chart.WithUnicode()
+	}
+	if h.colour {
+		chart = chart.WithColour()
+	}
+
+	return chart.PlotToWidth(h.width), nil
+}
 
-	scaleFactor := barchart.CalculateScaleFactor(bars, 100.0)
+func (h Handler) barChart(q Query, covidData []data) (barchart.BarChart, error) {
+	bars, gaps, err := h.bars(q, covidData)
+	if err != nil {
+		return barchart.BarChart{}, err
+	}
 
-	return chart.Plot(scaleFactor), nil
+	chart, err := barchart.NewBarChart(q.title(h.api.area().Name), bars)
+	if err != nil {
+		return barchart.BarChart{}, err
+	}
+	return chart.WithFormatter(q.formatter()).WithNote(gapNote(h.GapPolicy(), gaps)), nil
 }
 
-func (h Handler) GetDeathsChart(previousWeeks int) (string, error) {
-	covidData, err := h.api.getData(previousWeeks * 7)
+// bars is a bar per charted day of the fetched covidData, and how many of those days the api was
+// missing a figure for
+func (h Handler) bars(q Query, covidData []data) ([]barchart.Bar, int, error) {
+	covidData, gaps := h.fillGaps(covidData, q)
+
+	values, err := h.values(covidData, q)
 	if err != nil {
-		return "", err
+		return nil, 0, err
+	}
+
+	now := time.Now()
+	var bars []barchart.Bar
+	for i, d := range covidData {
+		if q.isCharted(d) {
+			bars = append(bars, dayBar(d, values[i], q.Metric, now))
+		}
+	}
+
+	return bars, gaps, nil
+}
+
+// dayBar is the day's bar, marked if its figure is incomplete, estimated or missing
+func dayBar(d data, value float64, metric Metric, now time.Time) barchart.Bar {
+	bar := barchart.NewBar(d.date.Format("02/01"), value)
+	if d.gap {
+		return bar.MarkGap()
+	}
+	if d.estimated {
+		bar = bar.MarkEstimated()
+	}
+	if metric.isIncomplete(d, now) {
+		bar = bar.MarkIncomplete()
+	}
+	return bar
+}
+
+// comparisonChart draws the fetched results for each of the areas side by side
+func (h Handler) comparisonChart(q Query, results [][]data) (string, error) {
+	// areas don't always report on the same days, so chart every day any of them has data for, unless
+	// the gap policy drops the days that are missing
+	now := time.Now()
+	barsByArea := make([]map[string]barchart.Bar, len(q.Compare))
+	datesByDay := make(map[string]time.Time)
+	gaps := 0
+	for i, covidData := range results {
+		covidData, areaGaps := h.fillGaps(covidData, q)
+		gaps += areaGaps
+
+		values, err := h.values(covidData, q)
+		if err != nil {
+			return "", err
+		}
+
+		barsByArea[i] = make(map[string]barchart.Bar)
+		for j, d := range covidData {
+			if q.isCharted(d) {
+				day := d.date.Format("2006-01-02")
+				barsByArea[i][day] = dayBar(d, values[j], q.Metric, now)
+				datesByDay[day] = d.date
+			}
+		}
+	}
+
+	var dates []time.Time
+	for day, date := range datesByDay {
+		if h.GapPolicy() == GapDrop && !chartedForAll(barsByArea, day) {
+			// leave the day out for every area, as it was for the one missing it
+			continue
+		}
+		dates = append(dates, date)
 	}
 
 	// sort oldest -> newest
-	sort.Slice(covidData, func(i, j int) bool {
-		return covidData[i].date.Before(covidData[j].date)
+	sort.Slice(dates, func(i, j int) bool {
+		return dates[i].Before(dates[j])
 	})
 
-	var bars []barchart.Bar
-	for _, d := range covidData {
-		bars = append(bars, barchart.NewBar(d.date.Format("02/01"), d.deaths))
+	var series []barchart.Series
+	for i, area := range q.Compare {
+		var bars []barchart.Bar
+		for _, date := range dates {
+			bar, ok := barsByArea[i][date.Format("2006-01-02")]
+			if !ok {
+				bar = barchart.NewBar(date.Format("02/01"), 0).MarkGap()
+			}
+			bars = append(bars, bar)
+		}
+		series = append(series, barchart.NewSeries(area.Name, bars))
+	}
+
+	var names []string
+	for _, area := range q.Compare {
+		names = append(names, area.Name)
+	}
+
+	title := q.title(strings.Join(names, " vs "))
+	note := gapNote(h.GapPolicy(), gaps)
+	if h.ChartStyle() == Sparkline {
+		return h.plotSparklines(title, series, q, note)
+	}
+
+	chart, err := barchart.NewGroupedBarChart(title, series)
+	if err != nil {
+		return "", err
+	}
+	chart = chart.WithFormatter(q.formatter()).WithNote(note)
+	if h.colour {
+		chart = chart.WithColour()
 	}
 
-	chart, err := barchart.NewBarChart("New deaths", bars)
+	return chart.PlotToWidth(h.width), nil
+}
+
+// chartedForAll reports whether every area has a bar for the day
+func chartedForAll(barsByArea []map[string]barchart.Bar, day string) bool {
+	for _, bars := range barsByArea {
+		if _, ok := bars[day]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func (h Handler) plotSparklines(title string, series []barchart.Series, q Query, note string) (string, error) {
+	chart, err := barchart.NewSparklines(title, series)
 	if err != nil {
 		return "", err
 	}
+	chart = chart.WithFormatter(q.formatter()).WithNote(note)
+	if h.unicode {
+		chart = chart.WithUnicode()
+	}
+	if h.colour {
+		chart = chart.WithColour()
+	}
+
+	return chart.PlotToWidth(h.width), nil
+}
 
-	scaleFactor := barchart.CalculateScaleFactor(bars, 100.0)
+// fetch gets the days the api has a figure for the metric on, sorted oldest -> newest
+func (h Handler) fetch(ctx context.Context, api restApi, metric Metric, previousDays int) ([]data, error) {
+	covidData, err := api.getData(ctx, previousDays, []Metric{metric})
+	if err != nil {
+		return nil, err
+	}
 
-	return chart.Plot(scaleFactor), nil
-}
\ No newline at end of file
+	var withValues []data
+	for _, d := range covidData {
+		if _, ok := d.value(metric); ok {
+			withValues = append(withValues, d)
+		}
+	}
+
+	if len(withValues) == 0 {
+		return nil, fmt.Errorf("%w on %s for %s", ErrEmptyResponse, strings.ToLower(metric.Title), api.area())
+	}
+
+	h.log.Debugf("fetched %d days of %s for %s", len(withValues), strings.ToLower(metric.Title), api.area())
+	sortOldestToNewest(withValues)
+	return withValues, nil
+}
+
+// values is the metric's figure for each day, as a rate if asked for and smoothed if asked for.
+// Gaps are 0 and left out of the averages
+func (h Handler) values(covidData []data, q Query) ([]float64, error) {
+	values := make([]float64, len(covidData))
+	for i, d := range covidData {
+		if d.gap {
+			continue
+		}
+		value, _ := d.value(q.Metric)
+		if q.Per100k {
+			var err error
+			value, err = h.populations.per100k(value, d.areaCode)
+			if err != nil {
+				return nil, err
+			}
+		}
+		values[i] = value
+	}
+
+	return smooth(covidData, values, q.RollingWindow), nil
+}
+
+// formatter shows rates with a decimal place so they can be told apart, and counts as whole numbers
+func (q Query) formatter() barchart.Formatter {
+	if q.Per100k && !q.Metric.rate {
+		return barchart.DecimalFormat(1)
+	}
+	return q.Metric.formatter()
+}
+
+func (q Query) title(areaName string) string {
+	title := q.Metric.Title
+	if q.Per100k {
+		title += " per 100k"
+	}
+	return q.smoothedTitle(chartTitle(title, Area{Name: areaName}))
+}
+
+func sortOldestToNewest(covidData []data) {
+	sort.Slice(covidData, func(i, j int) bool {
+		return covidData[i].date.Before(covidData[j].date)
+	})
+}
+
+func chartTitle(title string, area Area) string {
+	if area.Name == "" {
+		return title
+	}
+	return title + " in " + area.Name
+}
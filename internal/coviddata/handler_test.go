@@ -1,17 +1,23 @@
 package coviddata
 
 import (
+	"context"
+	"covid-stats-cli/internal/logging"
+	"covid-stats-cli/internal/terminal"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 )
 
-func TestHandler_GetDeathsChart_ApiReturnsError(t *testing.T) {
+func TestHandler_Run_DeathsChart_ApiReturnsError(t *testing.T) {
 	apiErr := errors.New("our data centre went bye bye")
 	mockApi := givenApiThatReturns(nil, apiErr)
-	handler := NewHandler(mockApi)
+	handler := NewHandler(mockApi, logging.Discard())
 
-	chart, err := handler.GetDeathsChart(5)
+	result, err := handler.Run(context.Background(), Query{Metric: Deaths, PreviousDays: 7,
+		Render: []Renderer{RenderChart}})
+	chart := result.Chart
 
 	if chart != "" || err != apiErr {
 		t.Fatalf("Expected error %v to equal %v and chart '%s' to be empty",
@@ -19,20 +25,22 @@ func TestHandler_GetDeathsChart_ApiReturnsError(t *testing.T) {
 	}
 }
 
-func TestHandler_GetDeathsChart_NewBarChartReturnsError(t *testing.T) {
-	emptyData := make([]data, 0) // barchart.NewBarChart will return an error when no data given
-	mockApi := givenApiThatReturns(emptyData, nil)
-	handler := NewHandler(mockApi)
+func TestHandler_Run_DeathsChart_ApiHasNoDataForMetric(t *testing.T) {
+	noData := []data{{date: time.Now().Add(time.Hour * -24), values: map[string]float64{}}}
+	mockApi := givenApiThatReturns(noData, nil)
+	mockApi.mockArea = England
+	handler := NewHandler(mockApi, logging.Discard())
 
-	chart, err := handler.GetDeathsChart(5)
+	result, err := handler.Run(context.Background(), Query{Metric: Deaths, PreviousDays: 7,
+		Render: []Renderer{RenderChart}})
+	chart := result.Chart
 
-	expectedErrorMsgFromBarChart := "there are no bars in the bar chart"
-	if chart != "" || (err == nil || err.Error() != expectedErrorMsgFromBarChart) {
-		t.Fatalf("got %s and %v", chart, err)
+	if chart != "" || !errors.Is(err, ErrEmptyResponse) {
+		t.Fatalf("Expected ErrEmptyResponse and no chart, got %s and %v", chart, err)
 	}
 }
 
-func TestHandler_GetDeathsChart_PlotsChartWithDatesSortedOldToNew(t *testing.T) {
+func TestHandler_Run_DeathsChart_PlotsChartWithDatesSortedOldToNew(t *testing.T) {
 	today := time.Now()
 	oneDayAgo := today.Add(time.Hour * -24)
 	twoDaysAgo := today.Add(time.Hour * -48)
@@ -42,48 +50,53 @@ func TestHandler_GetDeathsChart_PlotsChartWithDatesSortedOldToNew(t *testing.T)
 
 	deathsData := make([]data, 0)
 	deathsData = append(deathsData, data{
-		date:  twoDaysAgo,
-		deaths: 6,
+		date:   twoDaysAgo,
+		values: map[string]float64{"deaths": 6},
 	})
 	deathsData = append(deathsData, data{
-		date:  oneDayAgo,
-		deaths: 5,
+		date:   oneDayAgo,
+		values: map[string]float64{"deaths": 5},
 	})
 	deathsData = append(deathsData, data{
-		date:  threeDaysAgo,
-		deaths: 7,
+		date:   threeDaysAgo,
+		values: map[string]float64{"deaths": 7},
 	})
 	deathsData = append(deathsData, data{
-		date:  fourDaysAgo,
-		deaths: 12,
+		date:   fourDaysAgo,
+		values: map[string]float64{"deaths": 12},
 	})
 	deathsData = append(deathsData, data{
-		date:  fiveDaysAgo,
-		deaths: 10,
+		date:   fiveDaysAgo,
+		values: map[string]float64{"deaths": 10},
 	})
 
 	mockApi := givenApiThatReturns(deathsData, nil)
-	handler := NewHandler(mockApi)
+	handler := NewHandler(mockApi, logging.Discard())
+	handler.SetWidth(27)
 
 	expectedChart := "\n----- New deaths -----\n\n" +
-	fiveDaysAgo.Format("02/01") + " (10) | **********    \n" +
-	fourDaysAgo.Format("02/01") + " (12) | ************  \n" +
-	threeDaysAgo.Format("02/01") + " (7)  | *******       \n" +
-	twoDaysAgo.Format("02/01") + " (6)  | ******        \n" +
-	oneDayAgo.Format("02/01") + " (5)  | *****         \n\n"
-	chart, err := handler.GetDeathsChart(5)
+		fiveDaysAgo.Format("02/01") + " (10) | **********    \n" +
+		fourDaysAgo.Format("02/01") + " (12) | ************  \n" +
+		threeDaysAgo.Format("02/01") + " (7)  | *******       \n" +
+		twoDaysAgo.Format("02/01") + " (6)  | ******        \n" +
+		oneDayAgo.Format("02/01") + " (5)  | *****         \n\n"
+	result, err := handler.Run(context.Background(), Query{Metric: Deaths, PreviousDays: 5,
+		Render: []Renderer{RenderChart}})
+	chart := result.Chart
 
 	if chart != expectedChart || err != nil {
 		t.Fatalf("expected chart '%s' and nil err, but got chart '%s' and err '%v'", expectedChart, chart, err)
 	}
 }
 
-func TestHandler_GetCasesChart_ApiReturnsError(t *testing.T) {
+func TestHandler_Run_CasesChart_ApiReturnsError(t *testing.T) {
 	apiErr := errors.New("our data centre went bye bye")
 	mockApi := givenApiThatReturns(nil, apiErr)
-	handler := NewHandler(mockApi)
+	handler := NewHandler(mockApi, logging.Discard())
 
-	chart, err := handler.GetCasesChart(5)
+	result, err := handler.Run(context.Background(), Query{Metric: Cases, PreviousDays: 7,
+		Render: []Renderer{RenderChart}})
+	chart := result.Chart
 
 	if chart != "" || err != apiErr {
 		t.Fatalf("Expected error %v to equal %v and chart '%s' to be empty",
@@ -91,20 +104,22 @@ func TestHandler_GetCasesChart_ApiReturnsError(t *testing.T) {
 	}
 }
 
-func TestHandler_GetCasesChart_NewBarChartReturnsError(t *testing.T) {
-	emptyData := make([]data, 0) // barchart.NewBarChart will return an error when no data given
-	mockApi := givenApiThatReturns(emptyData, nil)
-	handler := NewHandler(mockApi)
+func TestHandler_Run_CasesChart_ApiHasNoDataForMetric(t *testing.T) {
+	noData := []data{{date: time.Now().Add(time.Hour * -24), values: map[string]float64{}}}
+	mockApi := givenApiThatReturns(noData, nil)
+	mockApi.mockArea = England
+	handler := NewHandler(mockApi, logging.Discard())
 
-	chart, err := handler.GetCasesChart(5)
+	result, err := handler.Run(context.Background(), Query{Metric: Cases, PreviousDays: 7,
+		Render: []Renderer{RenderChart}})
+	chart := result.Chart
 
-	expectedErrorMsgFromBarChart := "there are no bars in the bar chart"
-	if chart != "" || (err == nil || err.Error() != expectedErrorMsgFromBarChart) {
-		t.Fatalf("got %s and %v", chart, err)
+	if chart != "" || !errors.Is(err, ErrEmptyResponse) {
+		t.Fatalf("Expected ErrEmptyResponse and no chart, got %s and %v", chart, err)
 	}
 }
 
-func TestHandler_GetCasesChart_PlotsChartWithDatesSortedOldToNew(t *testing.T) {
+func TestHandler_Run_CasesChart_PlotsChartWithDatesSortedOldToNew(t *testing.T) {
 	today := time.Now()
 	oneDayAgo := today.Add(time.Hour * -24)
 	twoDaysAgo := today.Add(time.Hour * -48)
@@ -114,53 +129,362 @@ func TestHandler_GetCasesChart_PlotsChartWithDatesSortedOldToNew(t *testing.T) {
 
 	caseData := make([]data, 0)
 	caseData = append(caseData, data{
-		date:  twoDaysAgo,
-		cases: 6,
+		date:   twoDaysAgo,
+		values: map[string]float64{"cases": 6},
 	})
 	caseData = append(caseData, data{
-		date:  oneDayAgo,
-		cases: 5,
+		date:   oneDayAgo,
+		values: map[string]float64{"cases": 5},
 	})
 	caseData = append(caseData, data{
-		date:  threeDaysAgo,
-		cases: 7,
+		date:   threeDaysAgo,
+		values: map[string]float64{"cases": 7},
 	})
 	caseData = append(caseData, data{
-		date:  fourDaysAgo,
-		cases: 12,
+		date:   fourDaysAgo,
+		values: map[string]float64{"cases": 12},
 	})
 	caseData = append(caseData, data{
-		date:  fiveDaysAgo,
-		cases: 10,
+		date:   fiveDaysAgo,
+		values: map[string]float64{"cases": 10},
 	})
 
 	mockApi := givenApiThatReturns(caseData, nil)
-	handler := NewHandler(mockApi)
+	handler := NewHandler(mockApi, logging.Discard())
+	handler.SetWidth(27)
 
 	expectedChart := "\n----- New cases -----\n\n" +
-	fiveDaysAgo.Format("02/01") + " (10) | **********    \n" +
-	fourDaysAgo.Format("02/01") + " (12) | ************  \n" +
-	threeDaysAgo.Format("02/01") + " (7)  | *******       \n" +
-	twoDaysAgo.Format("02/01") + " (6)  | ******        \n" +
-	oneDayAgo.Format("02/01") + " (5)  | *****         \n\n"
-	chart, err := handler.GetCasesChart(5)
+		fiveDaysAgo.Format("02/01") + " (10) | **********    \n" +
+		fourDaysAgo.Format("02/01") + " (12) | ************  \n" +
+		threeDaysAgo.Format("02/01") + " (7)  | *******       \n" +
+		twoDaysAgo.Format("02/01") + " (6)  | ******        \n" +
+		oneDayAgo.Format("02/01") + " (5)  | *****         \n\n"
+	result, err := handler.Run(context.Background(), Query{Metric: Cases, PreviousDays: 5,
+		Render: []Renderer{RenderChart}})
+	chart := result.Chart
 
 	if chart != expectedChart || err != nil {
 		t.Fatalf("expected chart '%s' and nil err, but got chart '%s' and err '%v'", expectedChart, chart, err)
 	}
 }
 
+func TestHandler_Run_CasesChart_TitleIncludesArea(t *testing.T) {
+	caseData := []data{{date: time.Now().Add(time.Hour * -24), values: map[string]float64{"cases": 5}}}
+	mockApi := givenApiThatReturns(caseData, nil)
+	mockApi.mockArea = Area{Region, "London"}
+	handler := NewHandler(mockApi, logging.Discard())
+
+	result, err := handler.Run(context.Background(), Query{Metric: Cases, PreviousDays: 1,
+		Render: []Renderer{RenderChart}})
+	chart := result.Chart
+
+	if err != nil || !strings.HasPrefix(chart, "\n----- New cases in London -----\n") {
+		t.Fatalf("expected the chart title to include the area, but got chart '%s' and err '%v'", chart, err)
+	}
+}
+
+func TestHandler_Run_ComparisonChart_PlotsEachAreaForEveryDay(t *testing.T) {
+	oneDayAgo := time.Now().Add(time.Hour * -24)
+	twoDaysAgo := time.Now().Add(time.Hour * -48)
+
+	london := Area{Region, "London"}
+	leeds := Area{Ltla, "Leeds"}
+	dataByArea := map[Area][]data{
+		london: {{date: oneDayAgo, values: map[string]float64{"cases": 10}}, {date: twoDaysAgo, values: map[string]float64{"cases": 4}}},
+		leeds:  {{date: oneDayAgo, values: map[string]float64{"cases": 6}}}, // no data reported two days ago
+	}
+	mockApi := mockRestApi{mockGetData: func(area Area, _ int) ([]data, error) {
+		return dataByArea[area], nil
+	}}
+	handler := NewHandler(mockApi, logging.Discard())
+	handler.SetWidth(32)
+
+	expectedChart := "\n----- New cases in London vs Leeds -----\n\n" +
+		twoDaysAgo.Format("02/01") + " London (4)  | ****        \n" +
+		"      Leeds  (?)  |             \n" +
+		oneDayAgo.Format("02/01") + " London (10) | **********  \n" +
+		"      Leeds  (6)  | ######      \n\n" +
+		"Legend: * London  # Leeds\n\n" +
+		"Legend: ? the figure's missing\n\n" +
+		"Gaps: the api is missing 1 figure, left empty\n\n"
+	result, err := handler.Run(context.Background(), Query{Metric: Cases, PreviousDays: 2, Compare: []Area{london, leeds},
+		Render: []Renderer{RenderChart}})
+	chart := result.Chart
+
+	if chart != expectedChart || err != nil {
+		t.Fatalf("expected chart '%s' and nil err, but got chart '%s' and err '%v'", expectedChart, chart, err)
+	}
+}
+
+func TestHandler_Run_ComparisonChart_FillsGapsAsThePolicySays(t *testing.T) {
+	oneDayAgo := time.Now().Add(time.Hour * -24)
+	twoDaysAgo := time.Now().Add(time.Hour * -48)
+	threeDaysAgo := time.Now().Add(time.Hour * -72)
+
+	london := Area{Region, "London"}
+	leeds := Area{Ltla, "Leeds"}
+	dataByArea := map[Area][]data{
+		london: {
+			{date: oneDayAgo, values: map[string]float64{"cases": 10}},
+			{date: twoDaysAgo, values: map[string]float64{"cases": 8}},
+			{date: threeDaysAgo, values: map[string]float64{"cases": 6}},
+		},
+		leeds: { // no data reported two days ago
+			{date: oneDayAgo, values: map[string]float64{"cases": 6}},
+			{date: threeDaysAgo, values: map[string]float64{"cases": 2}},
+		},
+	}
+	mockApi := mockRestApi{mockGetData: func(area Area, _ int) ([]data, error) {
+		return dataByArea[area], nil
+	}}
+
+	tests := []struct {
+		policy   GapPolicy
+		expected []string
+	}{
+		{GapMark, []string{"Leeds  (?)", "Gaps: the api is missing 1 figure, left empty\n"}},
+		{GapCarryForward, []string{"Leeds  (~2)", "Gaps: the api is missing 1 figure, estimated by carrying forward"}},
+		{GapInterpolate, []string{"Leeds  (~4)", "Gaps: the api is missing 1 figure, estimated along a straight line"}},
+		{GapDrop, []string{"Gaps: the api is missing 1 figure, left out of the chart\n"}},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.policy), func(t *testing.T) {
+			handler := NewHandler(mockApi, logging.Discard())
+			handler.SetWidth(32)
+			handler.SetGapPolicy(test.policy)
+
+			result, err := handler.Run(context.Background(), Query{Metric: Cases, PreviousDays: 3,
+				Compare: []Area{london, leeds}, Render: []Renderer{RenderChart}})
+			chart := result.Chart
+
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, expected := range test.expected {
+				if !strings.Contains(chart, expected) {
+					t.Errorf("expected the chart to contain '%s', got %s", expected, chart)
+				}
+			}
+			if dropped := !strings.Contains(chart, twoDaysAgo.Format("02/01")); dropped != (test.policy == GapDrop) {
+				t.Errorf("expected %s to be left out for every area only when dropping gaps, got %s",
+					twoDaysAgo.Format("02/01"), chart)
+			}
+		})
+	}
+}
+
+func TestHandler_GetDeathsComparisonChart_ApiReturnsErrorForOneArea(t *testing.T) {
+	apiErr := errors.New("our data centre went bye bye")
+	london := Area{Region, "London"}
+	mockApi := mockRestApi{mockGetData: func(area Area, _ int) ([]data, error) {
+		if area == london {
+			return nil, apiErr
+		}
+		return []data{{date: time.Now().Add(time.Hour * -24), values: map[string]float64{"deaths": 1}}}, nil
+	}}
+	handler := NewHandler(mockApi, logging.Discard())
+
+	result, err := handler.Run(context.Background(), Query{Metric: Deaths, PreviousDays: 1, Compare: []Area{England, london},
+		Render: []Renderer{RenderChart}})
+	chart := result.Chart
+
+	if chart != "" || !errors.Is(err, apiErr) {
+		t.Fatalf("Expected error %v to wrap %v and chart '%s' to be empty", err, apiErr, chart)
+	}
+}
+
+func TestHandler_Run_Chart_ByEventDateFallsBackToPublishDate(t *testing.T) {
+	admissions := []data{{date: time.Now().Add(time.Hour * -24), values: map[string]float64{"admissions": 3}}}
+	handler := NewHandler(givenApiThatReturns(admissions, nil), logging.Discard())
+
+	result, err := handler.Run(context.Background(), Query{Metric: Admissions, PreviousDays: 7, DateBasis: EventDate,
+		Render: []Renderer{RenderChart}})
+
+	chart := result.Chart
+	if err != nil || !strings.HasPrefix(chart, "\n----- Hospital admissions by publish date -----\n") {
+		t.Fatalf("Expected a chart of admissions by publish date, got '%s' and err %v", chart, err)
+	}
+}
+
+func TestHandler_Run_CasesChart_BySpecimenDateMarksIncompleteDays(t *testing.T) {
+	today := time.Now()
+	var caseData []data
+	for day := 1; day <= 7; day++ {
+		caseData = append(caseData, data{date: today.Add(time.Duration(-24*day) * time.Hour), values: map[string]float64{"cases": 3}})
+	}
+	handler := NewHandler(givenApiThatReturns(caseData, nil), logging.Discard())
+
+	result, err := handler.Run(context.Background(), Query{Metric: Cases, PreviousDays: 7, DateBasis: EventDate,
+		Render: []Renderer{RenderChart}})
+
+	chart := result.Chart
+	if err != nil || !strings.HasPrefix(chart, "\n----- New cases by specimen date -----\n") {
+		t.Fatalf("Expected a chart of cases by specimen date, got '%s' and err %v", chart, err)
+	}
+
+	if strings.Count(chart, "| ***") != 2 || strings.Count(chart, "| ...") != 5 {
+		t.Fatalf("Expected the 5 most recent days to be marked incomplete, got '%s'", chart)
+	}
+}
+
+func TestHandler_Run_CasesChart_RatePer100k(t *testing.T) {
+	oneDayAgo := time.Now().Add(time.Hour * -24)
+	caseData := []data{{date: oneDayAgo, areaCode: "E06000001", values: map[string]float64{"cases": 5}}}
+	handler := NewHandler(givenApiThatReturns(caseData, nil), logging.Discard())
+	handler.SetPopulations(Populations{"E06000001": 40000})
+	handler.SetWidth(29)
+
+	result, err := handler.Run(context.Background(), Query{Metric: Cases, PreviousDays: 1, Per100k: true,
+		Render: []Renderer{RenderChart}})
+
+	chart := result.Chart
+	expectedChart := "\n----- New cases per 100k -----\n\n" +
+		oneDayAgo.Format("02/01") + " (12.5) | ************  \n\n"
+	if chart != expectedChart || err != nil {
+		t.Fatalf("expected chart '%s' and nil err, but got chart '%s' and err '%v'", expectedChart, chart, err)
+	}
+}
+
+func TestHandler_Run_CasesChart_VerticalStyleDrawsColumns(t *testing.T) {
+	oneDayAgo := time.Now().Add(time.Hour * -24)
+	twoDaysAgo := time.Now().Add(time.Hour * -48)
+	caseData := []data{
+		{date: oneDayAgo, values: map[string]float64{"cases": 10}},
+		{date: twoDaysAgo, values: map[string]float64{"cases": 5}},
+	}
+	handler := NewHandler(givenApiThatReturns(caseData, nil), logging.Discard())
+	handler.SetChartStyle(Vertical)
+
+	result, err := handler.Run(context.Background(), Query{Metric: Cases, PreviousDays: 2,
+		Render: []Renderer{RenderChart}})
+	chart := result.Chart
+
+	labels := "\n     " + twoDaysAgo.Format("02/01") + "\n"
+	if err != nil || !strings.Contains(chart, "10 |     ***\n") || !strings.Contains(chart, labels) {
+		t.Fatalf("Expected the cases as columns, oldest first, got '%s' and err %v", chart, err)
+	}
+}
+
+func TestHandler_Run_CasesChart_BlocksStyleFallsBackToAsciiWithoutUnicode(t *testing.T) {
+	caseData := []data{{date: time.Now().Add(time.Hour * -24), values: map[string]float64{"cases": 5}}}
+	handler := NewHandler(givenApiThatReturns(caseData, nil), logging.Discard())
+	handler.SetChartStyle(Blocks)
+
+	result, err := handler.Run(context.Background(), Query{Metric: Cases, PreviousDays: 1,
+		Render: []Renderer{RenderChart}})
+	ascii := result.Chart
+	if err != nil || !strings.Contains(ascii, "*****") || strings.Contains(ascii, "█") {
+		t.Fatalf("Expected the chart to be drawn with * without unicode, got '%s' and err %v", ascii, err)
+	}
+
+	handler.SetUnicode(true)
+
+	result, err = handler.Run(context.Background(), Query{Metric: Cases, PreviousDays: 1,
+		Render: []Renderer{RenderChart}})
+	blocks := result.Chart
+	if err != nil || !strings.Contains(blocks, "█") {
+		t.Fatalf("Expected the chart to be drawn with blocks, got '%s' and err %v", blocks, err)
+	}
+}
+
+func TestHandler_Run_ComparisonChart_SparklinesDrawALinePerArea(t *testing.T) {
+	oneDayAgo := time.Now().Add(time.Hour * -24)
+	twoDaysAgo := time.Now().Add(time.Hour * -48)
+
+	london := Area{Region, "London"}
+	leeds := Area{Ltla, "Leeds"}
+	dataByArea := map[Area][]data{
+		london: {{date: oneDayAgo, values: map[string]float64{"cases": 10}}, {date: twoDaysAgo, values: map[string]float64{"cases": 4}}},
+		leeds:  {{date: oneDayAgo, values: map[string]float64{"cases": 6}}, {date: twoDaysAgo, values: map[string]float64{"cases": 12}}},
+	}
+	mockApi := mockRestApi{mockGetData: func(area Area, _ int) ([]data, error) {
+		return dataByArea[area], nil
+	}}
+	handler := NewHandler(mockApi, logging.Discard())
+	handler.SetChartStyle(Sparkline)
+	handler.SetUnicode(true)
+
+	result, err := handler.Run(context.Background(), Query{Metric: Cases, PreviousDays: 2, Compare: []Area{london, leeds},
+		Render: []Renderer{RenderChart}})
+	chart := result.Chart
+
+	if err != nil || !strings.Contains(chart, "London ▃█  latest 10, peak 10\n") ||
+		!strings.Contains(chart, "Leeds  █▄  latest 6, peak 12\n") {
+		t.Fatalf("Expected a sparkline for each area, got '%s' and err %v", chart, err)
+	}
+}
+
+func TestHandler_Run_CasesChart_Colour(t *testing.T) {
+	caseData := []data{{date: time.Now().Add(time.Hour * -24), values: map[string]float64{"cases": 5}}}
+	handler := NewHandler(givenApiThatReturns(caseData, nil), logging.Discard())
+
+	result, err := handler.Run(context.Background(), Query{Metric: Cases, PreviousDays: 1,
+		Render: []Renderer{RenderChart}})
+	plain := result.Chart
+	if err != nil || strings.Contains(plain, "\x1b[") {
+		t.Fatalf("Expected no colour by default, got '%q' and err %v", plain, err)
+	}
+
+	handler.SetColour(true)
+
+	result, err = handler.Run(context.Background(), Query{Metric: Cases, PreviousDays: 1,
+		Render: []Renderer{RenderChart}})
+	coloured := result.Chart
+	if err != nil || !strings.Contains(coloured, "\x1b[") {
+		t.Fatalf("Expected the chart to be coloured, got '%q' and err %v", coloured, err)
+	}
+}
+
+func TestHandler_Run_BarChartIsTheChartPlotted(t *testing.T) {
+	oneDayAgo := time.Now().Add(time.Hour * -24)
+	caseData := []data{{date: oneDayAgo, values: map[string]float64{"cases": 1500}}}
+	mockApi := givenApiThatReturns(caseData, nil)
+	mockApi.mockArea = Area{Region, "London"}
+	handler := NewHandler(mockApi, logging.Discard())
+
+	result, err := handler.Run(context.Background(), Query{Metric: Cases, PreviousDays: 1,
+		Render: []Renderer{RenderBarChart}})
+	chart := result.BarChart
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err = handler.Run(context.Background(), Query{Metric: Cases, PreviousDays: 1,
+		Render: []Renderer{RenderChart}})
+	plotted := result.Chart
+	if err != nil || chart.PlotToWidth(terminal.DefaultWidth) != plotted {
+		t.Fatalf("Expected the bar chart to plot as '%s' but got '%s' and err %v",
+			plotted, chart.PlotToWidth(terminal.DefaultWidth), err)
+	}
+}
+
 type mockRestApi struct {
-	mockGetData func(previousDays int) ([]data, error)
+	mockGetData     func(area Area, previousDays int) ([]data, error)
+	mockArea        Area
+	mockLastUpdated time.Time
+}
+
+func (m mockRestApi) getData(_ context.Context, previousDays int, _ []Metric) ([]data, error) {
+	return m.mockGetData(m.mockArea, previousDays)
+}
+
+func (m mockRestApi) forArea(area Area) restApi {
+	m.mockArea = area
+	return m
+}
+
+func (m mockRestApi) area() Area {
+	return m.mockArea
 }
 
-func (m mockRestApi) getData(previousDays int) ([]data, error) {
-	return m.mockGetData(previousDays)
+func (m mockRestApi) lastUpdated() time.Time {
+	return m.mockLastUpdated
 }
 
 func givenApiThatReturns(d []data, e error) mockRestApi {
-	mf := func(i int) ([]data, error) {
+	mf := func(_ Area, i int) ([]data, error) {
 		return d, e
 	}
-	return mockRestApi{mf}
+	return mockRestApi{mockGetData: mf}
 }
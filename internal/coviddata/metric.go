@@ -0,0 +1,136 @@
+package coviddata
+
+import (
+	"covid-stats-cli/internal/barchart"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type DateBasis string
+
+const (
+	// PublishDate charts each figure on the day it was published
+	PublishDate DateBasis = "publish"
+	// EventDate charts each figure on the day it happened, e.g. the specimen date for cases or the
+	// date of death for deaths. It's a truer curve but the last few days are incomplete
+	EventDate DateBasis = "event"
+)
+
+// the number of recent days still being reported when charting by event date
+const incompleteDays = 5
+
+// ParseDateBasis reads the date basis to chart the metric on. event charts any metric by when it
+// happened, where it's published that way, and the metric's own name for it, e.g. specimen for cases,
+// is accepted too
+func ParseDateBasis(basis string, metric Metric) (DateBasis, error) {
+	switch name := strings.ToLower(strings.TrimSpace(basis)); {
+	case name == "publish":
+		return PublishDate, nil
+	case name == "event", name != "" && name == metric.eventBasis:
+		return EventDate, nil
+	}
+
+	choices := "publish, event"
+	if metric.eventBasis != "" {
+		choices += ", " + metric.eventBasis
+	}
+	return "", fmt.Errorf("'%s' isn't a date basis for %s, choose one of: %s", basis, strings.ToLower(metric.Title), choices)
+}
+
+type Metric struct {
+	// Name identifies the metric on the command line and in exports, e.g. cases
+	Name  string
+	Title string
+	// the field the dashboard api publishes the metric under
+	field string
+	// level metrics count how many there are at a point in time, e.g. patients in hospital, rather
+	// than how many new ones there were, so a week's figure is an average rather than a total
+	level bool
+	// rate metrics are already relative to something, e.g. a percentage, so can't be put per 100k
+	rate bool
+	// the field and label for the metric by event date, if the api has it, and the date basis it can
+	// be asked for by as well as event, e.g. specimen
+	eventField string
+	eventLabel string
+	eventBasis string
+	// how many of the most recent days aren't fully reported yet
+	incompleteDays int
+}
+
+var (
+	Cases = Metric{Name: "cases", Title: "New cases", field: "newCasesByPublishDate",
+		eventField: "newCasesBySpecimenDate", eventLabel: "specimen date", eventBasis: "specimen"}
+	Deaths = Metric{Name: "deaths", Title: "New deaths", field: "newDeaths28DaysByPublishDate",
+		eventField: "newDeaths28DaysByDeathDate", eventLabel: "date of death", eventBasis: "death"}
+	Admissions     = Metric{Name: "admissions", Title: "Hospital admissions", field: "newAdmissions"}
+	HospitalCases  = Metric{Name: "hospital", Title: "Patients in hospital", field: "hospitalCases", level: true}
+	VentilatorBeds = Metric{Name: "ventilators", Title: "Patients on ventilators", field: "covidOccupiedMVBeds", level: true}
+	Tests          = Metric{Name: "tests", Title: "Tests conducted", field: "newVirusTests"}
+	PositivityRate = Metric{Name: "positivity", Title: "Positivity rate (%)",
+		field: "uniqueCasePositivityBySpecimenDateRollingSum", level: true, rate: true}
+	Vaccinations = Metric{Name: "vaccinations", Title: "Vaccination doses", field: "newVaccinesGivenByPublishDate"}
+)
+
+// the order the metrics are offered in the menu
+var Metrics = []Metric{Cases, Deaths, Admissions, HospitalCases, VentilatorBeds, Tests, PositivityRate, Vaccinations}
+
+func MetricByName(name string) (Metric, error) {
+	var names []string
+	for _, metric := range Metrics {
+		if strings.EqualFold(metric.Name, strings.TrimSpace(name)) {
+			return metric, nil
+		}
+		names = append(names, metric.Name)
+	}
+
+	return Metric{}, fmt.Errorf("'%s' isn't a metric I know about, choose one of: %s", name, strings.Join(names, ", "))
+}
+
+// ByDate returns the metric as published on the given date basis. A metric that's only published by
+// publish date is charted by that instead, saying so in its title
+func (m Metric) ByDate(basis DateBasis) Metric {
+	if basis == PublishDate || basis == "" {
+		return m
+	}
+
+	if m.eventField == "" {
+		m.Title += " by publish date"
+		return m
+	}
+
+	m.Title += " by " + m.eventLabel
+	m.field = m.eventField
+	m.incompleteDays = incompleteDays
+	return m
+}
+
+// isIncomplete reports whether the figure for the day may still be revised upwards
+func (m Metric) isIncomplete(d data, now time.Time) bool {
+	if m.incompleteDays == 0 {
+		return false
+	}
+
+	return isOnOrAfter(now.Add(time.Duration(-m.incompleteDays*24)*time.Hour), d.date)
+}
+
+func (m Metric) String() string {
+	return m.Name
+}
+
+// formatter shows the metric's figures, as a percentage for a rate and a whole number otherwise
+func (m Metric) formatter() barchart.Formatter {
+	if m.rate {
+		return barchart.PercentFormat
+	}
+	return barchart.ThousandsFormat
+}
+
+// structure is the api's structure parameter, which maps each metric's field to its name in the response
+func structure(metrics []Metric) string {
+	s := "{\"date\":\"date\",\"areaCode\":\"areaCode\""
+	for _, metric := range metrics {
+		s += ",\"" + metric.Name + "\":\"" + metric.field + "\""
+	}
+	return s + "}"
+}
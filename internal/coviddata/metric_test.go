@@ -0,0 +1,95 @@
+package coviddata
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMetricByName(t *testing.T) {
+	metric, err := MetricByName(" Hospital ")
+
+	if err != nil || metric != HospitalCases {
+		t.Fatalf("Expected %+v but got %+v and err %v", HospitalCases, metric, err)
+	}
+}
+
+func TestMetricByName_UnknownMetric(t *testing.T) {
+	_, err := MetricByName("vibes")
+
+	if err == nil {
+		t.Fatalf("MetricByName() should return an error for an unknown metric")
+	}
+}
+
+func TestStructure(t *testing.T) {
+	s := structure([]Metric{Cases, PositivityRate})
+
+	expected := "{\"date\":\"date\",\"areaCode\":\"areaCode\",\"cases\":\"newCasesByPublishDate\"," +
+		"\"positivity\":\"uniqueCasePositivityBySpecimenDateRollingSum\"}"
+	if s != expected {
+		t.Fatalf("Expected structure %s but got %s", expected, s)
+	}
+}
+
+func TestSummarise_AveragesLevelMetrics(t *testing.T) {
+	now := time.Now()
+	var covidData []data
+	for daysAgo := 14; daysAgo >= 1; daysAgo-- {
+		covidData = append(covidData, data{
+			date:   now.Add(time.Duration(-24*daysAgo) * time.Hour),
+			values: map[string]float64{"hospital": float64(100 * daysAgo)},
+		})
+	}
+
+	summary := summarise("Patients in hospital", covidData, 14, now, HospitalCases)
+
+	if !summary.Averaged || summary.ThisWeek != 400 || summary.LastWeek != 1100 {
+		t.Fatalf("Expected daily averages of 400 and 1100, got %+v", summary)
+	}
+}
+
+func TestMetric_ByDate_EventDate(t *testing.T) {
+	metric := Deaths.ByDate(EventDate)
+
+	if metric.field != "newDeaths28DaysByDeathDate" || metric.Title != "New deaths by date of death" {
+		t.Fatalf("Expected deaths by date of death but got %+v", metric)
+	}
+}
+
+func TestMetric_ByDate_NotPublishedByEventDate(t *testing.T) {
+	metric := Admissions.ByDate(EventDate)
+
+	if metric.field != Admissions.field || metric.Title != "Hospital admissions by publish date" {
+		t.Fatalf("Expected admissions by publish date, saying so in the title, but got %+v", metric)
+	}
+}
+
+func TestParseDateBasis(t *testing.T) {
+	tests := []struct {
+		basis    string
+		metric   Metric
+		expected DateBasis
+		fails    bool
+	}{
+		{basis: "publish", metric: Cases, expected: PublishDate},
+		{basis: "Event", metric: Deaths, expected: EventDate},
+		{basis: "event", metric: Admissions, expected: EventDate},
+		{basis: "Specimen", metric: Cases, expected: EventDate},
+		{basis: "death", metric: Deaths, expected: EventDate},
+		{basis: "death", metric: Cases, fails: true},
+		{basis: "specimen", metric: Deaths, fails: true},
+		{basis: "specimen", metric: Admissions, fails: true},
+		{basis: "whenever", metric: Cases, fails: true},
+	}
+
+	for _, test := range tests {
+		basis, err := ParseDateBasis(test.basis, test.metric)
+
+		if test.fails && err == nil {
+			t.Errorf("Expected %s to be rejected for %s, got %s", test.basis, test.metric, basis)
+		}
+		if !test.fails && (err != nil || basis != test.expected) {
+			t.Errorf("Expected %s to mean %s for %s but got %s and err %v", test.basis, test.expected, test.metric, basis, err)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package coviddata
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Populations are the number of people living in each area, keyed by the area's ons code
+type Populations map[string]int
+
+// DefaultPopulations returns a copy of the built in estimates that's safe to override
+func DefaultPopulations() Populations {
+	populations := make(Populations)
+	for code, population := range midYearEstimates {
+		populations[code] = population
+	}
+	return populations
+}
+
+// Override reads a csv of area code and population pairs, e.g. E06000001,93663, replacing or adding
+// to the populations already known about. Blank lines and lines starting with # are skipped
+func (p Populations) Override(r io.Reader) error {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = 2
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		code := strings.TrimSpace(record[0])
+		population, err := strconv.Atoi(strings.TrimSpace(record[1]))
+		if err != nil || population <= 0 {
+			return fmt.Errorf("the population for %s should be a positive whole number, got '%s'", code, record[1])
+		}
+
+		p[code] = population
+	}
+}
+
+// per100k converts a figure into the rate per 100,000 people living in the area
+func (p Populations) per100k(value float64, areaCode string) (float64, error) {
+	population, ok := p[areaCode]
+	if !ok {
+		return 0, fmt.Errorf("there's no population for area code %s, add it to a population override file", areaCode)
+	}
+
+	return value / float64(population) * 100000, nil
+}
@@ -0,0 +1,461 @@
+package coviddata
+
+// ons mid-2019 population estimates for every area type the dashboard charts. The dashboard reports
+// Cornwall with the Isles of Scilly and Hackney with the City of London, so they're counted together
+// under Cornwall's and Hackney's codes. Anything missing or out of date can be replaced with an
+// override file
+var midYearEstimates = Populations{
+	// the uk and its nations
+	"K02000001": 66796807, // United Kingdom
+	"E92000001": 56286961, // England
+	"N92000002": 1893667,  // Northern Ireland
+	"S92000003": 5463300,  // Scotland
+	"W92000004": 3152879,  // Wales
+
+	// the english regions
+	"E12000001": 2669941, // North East
+	"E12000002": 7341196, // North West
+	"E12000003": 5502967, // Yorkshire and The Humber
+	"E12000004": 4835928, // East Midlands
+	"E12000005": 5934037, // West Midlands
+	"E12000006": 6236072, // East of England
+	"E12000007": 8961989, // London
+	"E12000008": 9180135, // South East
+	"E12000009": 5624696, // South West
+
+	// the nhs regions, from the regions they're made up of
+	"E40000003": 8961989,  // London
+	"E40000005": 9180135,  // South East
+	"E40000006": 5624696,  // South West
+	"E40000007": 6236072,  // East of England
+	"E40000008": 10769965, // Midlands
+	"E40000009": 8172908,  // North East and Yorkshire
+	"E40000010": 7341196,  // North West
+
+	// the english counties, which are upper tier authorities made up of the districts below
+	"E10000002": 540162,  // Buckinghamshire
+	"E10000003": 654497,  // Cambridgeshire
+	"E10000006": 500012,  // Cumbria
+	"E10000007": 803995,  // Derbyshire
+	"E10000008": 801468,  // Devon
+	"E10000011": 556838,  // East Sussex
+	"E10000012": 1489331, // Essex
+	"E10000013": 638011,  // Gloucestershire
+	"E10000014": 1383636, // Hampshire
+	"E10000015": 1188625, // Hertfordshire
+	"E10000016": 1579353, // Kent
+	"E10000017": 1219145, // Lancashire
+	"E10000018": 706770,  // Leicestershire
+	"E10000019": 761461,  // Lincolnshire
+	"E10000020": 909079,  // Norfolk
+	"E10000021": 753049,  // Northamptonshire
+	"E10000023": 618032,  // North Yorkshire
+	"E10000024": 825840,  // Nottinghamshire
+	"E10000025": 691667,  // Oxfordshire
+	"E10000027": 561838,  // Somerset
+	"E10000028": 879261,  // Staffordshire
+	"E10000029": 761969,  // Suffolk
+	"E10000030": 1196449, // Surrey
+	"E10000031": 578997,  // Warwickshire
+	"E10000032": 863980,  // West Sussex
+	"E10000034": 595697,  // Worcestershire
+
+	// the unitary authorities, which are both upper and lower tier
+	"E06000001": 93663,  // Hartlepool
+	"E06000002": 140980, // Middlesbrough
+	"E06000003": 137150, // Redcar and Cleveland
+	"E06000004": 197348, // Stockton-on-Tees
+	"E06000005": 106803, // Darlington
+	"E06000006": 129410, // Halton
+	"E06000007": 210014, // Warrington
+	"E06000008": 149696, // Blackburn with Darwen
+	"E06000009": 139446, // Blackpool
+	"E06000010": 259778, // Kingston upon Hull, City of
+	"E06000011": 341173, // East Riding of Yorkshire
+	"E06000012": 159563, // North East Lincolnshire
+	"E06000013": 172292, // North Lincolnshire
+	"E06000014": 210618, // York
+	"E06000015": 257302, // Derby
+	"E06000016": 354224, // Leicester
+	"E06000017": 39697,  // Rutland
+	"E06000018": 332900, // Nottingham
+	"E06000019": 192801, // Herefordshire, County of
+	"E06000020": 179854, // Telford and Wrekin
+	"E06000021": 256375, // Stoke-on-Trent
+	"E06000022": 193282, // Bath and North East Somerset
+	"E06000023": 463377, // Bristol, City of
+	"E06000024": 215052, // North Somerset
+	"E06000025": 285093, // South Gloucestershire
+	"E06000026": 262100, // Plymouth
+	"E06000027": 136264, // Torbay
+	"E06000030": 222193, // Swindon
+	"E06000031": 202259, // Peterborough
+	"E06000032": 213528, // Luton
+	"E06000033": 182463, // Southend-on-Sea
+	"E06000034": 174341, // Thurrock
+	"E06000035": 278556, // Medway
+	"E06000036": 124165, // Bracknell Forest
+	"E06000037": 158450, // West Berkshire
+	"E06000038": 163203, // Reading
+	"E06000039": 149539, // Slough
+	"E06000040": 151273, // Windsor and Maidenhead
+	"E06000041": 171119, // Wokingham
+	"E06000042": 269457, // Milton Keynes
+	"E06000043": 290395, // Brighton and Hove
+	"E06000044": 215133, // Portsmouth
+	"E06000045": 252796, // Southampton
+	"E06000046": 141771, // Isle of Wight
+	"E06000047": 530094, // County Durham
+	"E06000049": 384152, // Cheshire East
+	"E06000050": 343071, // Cheshire West and Chester
+	"E06000051": 323136, // Shropshire
+	"E06000052": 571802, // Cornwall and Isles of Scilly
+	"E06000053": 2224,   // Isles of Scilly
+	"E06000054": 500024, // Wiltshire
+	"E06000055": 173292, // Bedford
+	"E06000056": 288648, // Central Bedfordshire
+	"E06000057": 322434, // Northumberland
+	"E06000058": 395784, // Bournemouth, Christchurch and Poole
+	"E06000059": 378508, // Dorset
+	"E06000060": 540162, // Buckinghamshire, unitary since 2020
+	"E06000061": 347999, // North Northamptonshire, unitary since 2021
+	"E06000062": 405050, // West Northamptonshire, unitary since 2021
+
+	// the districts of the counties
+	"E07000004": 199448, // Aylesbury Vale
+	"E07000005": 95927,  // Chiltern
+	"E07000006": 70043,  // South Bucks
+	"E07000007": 174744, // Wycombe
+	"E07000008": 125758, // Cambridge
+	"E07000009": 89840,  // East Cambridgeshire
+	"E07000010": 101850, // Fenland
+	"E07000011": 177963, // Huntingdonshire
+	"E07000012": 159086, // South Cambridgeshire
+	"E07000026": 97761,  // Allerdale
+	"E07000027": 67049,  // Barrow-in-Furness
+	"E07000028": 108678, // Carlisle
+	"E07000029": 68183,  // Copeland
+	"E07000030": 53253,  // Eden
+	"E07000031": 105088, // South Lakeland
+	"E07000032": 128147, // Amber Valley
+	"E07000033": 80562,  // Bolsover
+	"E07000034": 105318, // Chesterfield
+	"E07000035": 72325,  // Derbyshire Dales
+	"E07000036": 115490, // Erewash
+	"E07000037": 92901,  // High Peak
+	"E07000038": 101991, // North East Derbyshire
+	"E07000039": 107261, // South Derbyshire
+	"E07000040": 146284, // East Devon
+	"E07000041": 131405, // Exeter
+	"E07000042": 82311,  // Mid Devon
+	"E07000043": 97145,  // North Devon
+	"E07000044": 86221,  // South Hams
+	"E07000045": 134163, // Teignbridge
+	"E07000046": 68143,  // Torridge
+	"E07000047": 55796,  // West Devon
+	"E07000061": 103160, // Eastbourne
+	"E07000062": 92855,  // Hastings
+	"E07000063": 103268, // Lewes
+	"E07000064": 96080,  // Rother
+	"E07000065": 161475, // Wealden
+	"E07000066": 187199, // Basildon
+	"E07000067": 152604, // Braintree
+	"E07000068": 77021,  // Brentwood
+	"E07000069": 90376,  // Castle Point
+	"E07000070": 178388, // Chelmsford
+	"E07000071": 194706, // Colchester
+	"E07000072": 131689, // Epping Forest
+	"E07000073": 87067,  // Harlow
+	"E07000074": 65068,  // Maldon
+	"E07000075": 87368,  // Rochford
+	"E07000076": 146561, // Tendring
+	"E07000077": 91284,  // Uttlesford
+	"E07000078": 117090, // Cheltenham
+	"E07000079": 89862,  // Cotswold
+	"E07000080": 86791,  // Forest of Dean
+	"E07000081": 129285, // Gloucester
+	"E07000082": 119964, // Stroud
+	"E07000083": 95019,  // Tewkesbury
+	"E07000084": 176582, // Basingstoke and Deane
+	"E07000085": 122308, // East Hampshire
+	"E07000086": 133584, // Eastleigh
+	"E07000087": 116339, // Fareham
+	"E07000088": 85283,  // Gosport
+	"E07000089": 97073,  // Hart
+	"E07000090": 126220, // Havant
+	"E07000091": 180086, // New Forest
+	"E07000092": 95142,  // Rushmoor
+	"E07000093": 126160, // Test Valley
+	"E07000094": 124859, // Winchester
+	"E07000095": 97279,  // Broxbourne
+	"E07000096": 154763, // Dacorum
+	"E07000098": 104919, // Hertsmere
+	"E07000099": 133570, // North Hertfordshire
+	"E07000102": 93323,  // Three Rivers
+	"E07000103": 96577,  // Watford
+	"E07000240": 148452, // St Albans
+	"E07000241": 123043, // Welwyn Hatfield
+	"E07000242": 148854, // East Hertfordshire
+	"E07000243": 87845,  // Stevenage
+	"E07000105": 130032, // Ashford
+	"E07000106": 165394, // Canterbury
+	"E07000107": 112606, // Dartford
+	"E07000108": 118131, // Dover
+	"E07000109": 106385, // Gravesham
+	"E07000110": 171826, // Maidstone
+	"E07000111": 120293, // Sevenoaks
+	"E07000112": 112578, // Folkestone and Hythe
+	"E07000113": 150082, // Swale
+	"E07000114": 141819, // Thanet
+	"E07000115": 132153, // Tonbridge and Malling
+	"E07000116": 118054, // Tunbridge Wells
+	"E07000117": 88527,  // Burnley
+	"E07000118": 118216, // Chorley
+	"E07000119": 80780,  // Fylde
+	"E07000120": 81043,  // Hyndburn
+	"E07000121": 146038, // Lancaster
+	"E07000122": 92112,  // Pendle
+	"E07000123": 143135, // Preston
+	"E07000124": 60888,  // Ribble Valley
+	"E07000125": 71482,  // Rossendale
+	"E07000126": 110527, // South Ribble
+	"E07000127": 114306, // West Lancashire
+	"E07000128": 112091, // Wyre
+	"E07000129": 101526, // Blaby
+	"E07000130": 185851, // Charnwood
+	"E07000131": 93807,  // Harborough
+	"E07000132": 113640, // Hinckley and Bosworth
+	"E07000133": 51209,  // Melton
+	"E07000134": 103611, // North West Leicestershire
+	"E07000135": 57126,  // Oadby and Wigston
+	"E07000136": 70173,  // Boston
+	"E07000137": 141727, // East Lindsey
+	"E07000138": 99299,  // Lincoln
+	"E07000139": 117152, // North Kesteven
+	"E07000140": 95019,  // South Holland
+	"E07000141": 142424, // South Kesteven
+	"E07000142": 95667,  // West Lindsey
+	"E07000143": 139968, // Breckland
+	"E07000144": 130783, // Broadland
+	"E07000145": 99336,  // Great Yarmouth
+	"E07000146": 151811, // King's Lynn and West Norfolk
+	"E07000147": 105164, // North Norfolk
+	"E07000148": 141137, // Norwich
+	"E07000149": 140880, // South Norfolk
+	"E07000150": 72218,  // Corby
+	"E07000151": 85950,  // Daventry
+	"E07000152": 94527,  // East Northamptonshire
+	"E07000153": 101776, // Kettering
+	"E07000154": 224610, // Northampton
+	"E07000155": 94490,  // South Northamptonshire
+	"E07000156": 79478,  // Wellingborough
+	"E07000163": 57142,  // Craven
+	"E07000164": 91594,  // Hambleton
+	"E07000165": 160830, // Harrogate
+	"E07000166": 53730,  // Richmondshire
+	"E07000167": 55380,  // Ryedale
+	"E07000168": 108736, // Scarborough
+	"E07000169": 90620,  // Selby
+	"E07000170": 127918, // Ashfield
+	"E07000171": 117459, // Bassetlaw
+	"E07000172": 113272, // Broxtowe
+	"E07000173": 117786, // Gedling
+	"E07000174": 109313, // Mansfield
+	"E07000175": 122421, // Newark and Sherwood
+	"E07000176": 117671, // Rushcliffe
+	"E07000177": 150503, // Cherwell
+	"E07000178": 152457, // Oxford
+	"E07000179": 142057, // South Oxfordshire
+	"E07000180": 136007, // Vale of White Horse
+	"E07000181": 110643, // West Oxfordshire
+	"E07000187": 115587, // Mendip
+	"E07000188": 122791, // Sedgemoor
+	"E07000189": 168345, // South Somerset
+	"E07000246": 155115, // Somerset West and Taunton
+	"E07000192": 100762, // Cannock Chase
+	"E07000193": 119754, // East Staffordshire
+	"E07000194": 104756, // Lichfield
+	"E07000195": 129490, // Newcastle-under-Lyme
+	"E07000196": 112126, // South Staffordshire
+	"E07000197": 137280, // Stafford
+	"E07000198": 98397,  // Staffordshire Moorlands
+	"E07000199": 76696,  // Tamworth
+	"E07000200": 92036,  // Babergh
+	"E07000202": 137532, // Ipswich
+	"E07000203": 103895, // Mid Suffolk
+	"E07000244": 249461, // East Suffolk
+	"E07000245": 179045, // West Suffolk
+	"E07000207": 136626, // Elmbridge
+	"E07000208": 80627,  // Epsom and Ewell
+	"E07000209": 148998, // Guildford
+	"E07000210": 87253,  // Mole Valley
+	"E07000211": 148748, // Reigate and Banstead
+	"E07000212": 89424,  // Runnymede
+	"E07000213": 99844,  // Spelthorne
+	"E07000214": 89305,  // Surrey Heath
+	"E07000215": 88129,  // Tandridge
+	"E07000216": 126328, // Waverley
+	"E07000217": 101167, // Woking
+	"E07000218": 65264,  // North Warwickshire
+	"E07000219": 129883, // Nuneaton and Bedworth
+	"E07000220": 109999, // Rugby
+	"E07000221": 130098, // Stratford-on-Avon
+	"E07000222": 143753, // Warwick
+	"E07000223": 64301,  // Adur
+	"E07000224": 160758, // Arun
+	"E07000225": 121129, // Chichester
+	"E07000226": 112409, // Crawley
+	"E07000227": 143791, // Horsham
+	"E07000228": 151022, // Mid Sussex
+	"E07000229": 110570, // Worthing
+	"E07000234": 99881,  // Bromsgrove
+	"E07000235": 78113,  // Malvern Hills
+	"E07000236": 85317,  // Redditch
+	"E07000237": 101891, // Worcester
+	"E07000238": 129433, // Wychavon
+	"E07000239": 101062, // Wyre Forest
+
+	// the metropolitan boroughs
+	"E08000001": 287550,  // Bolton
+	"E08000002": 190990,  // Bury
+	"E08000003": 552858,  // Manchester
+	"E08000004": 237110,  // Oldham
+	"E08000005": 222412,  // Rochdale
+	"E08000006": 258834,  // Salford
+	"E08000007": 293423,  // Stockport
+	"E08000008": 226493,  // Tameside
+	"E08000009": 237354,  // Trafford
+	"E08000010": 328662,  // Wigan
+	"E08000011": 150862,  // Knowsley
+	"E08000012": 498042,  // Liverpool
+	"E08000013": 180585,  // St. Helens
+	"E08000014": 276410,  // Sefton
+	"E08000015": 324011,  // Wirral
+	"E08000016": 246866,  // Barnsley
+	"E08000017": 311890,  // Doncaster
+	"E08000018": 265411,  // Rotherham
+	"E08000019": 584853,  // Sheffield
+	"E08000021": 302820,  // Newcastle upon Tyne
+	"E08000022": 207913,  // North Tyneside
+	"E08000023": 150976,  // South Tyneside
+	"E08000024": 277705,  // Sunderland
+	"E08000025": 1141816, // Birmingham
+	"E08000026": 371521,  // Coventry
+	"E08000027": 321596,  // Dudley
+	"E08000028": 328450,  // Sandwell
+	"E08000029": 216374,  // Solihull
+	"E08000030": 285478,  // Walsall
+	"E08000031": 263357,  // Wolverhampton
+	"E08000032": 539776,  // Bradford
+	"E08000033": 211455,  // Calderdale
+	"E08000034": 439787,  // Kirklees
+	"E08000035": 793139,  // Leeds
+	"E08000036": 348312,  // Wakefield
+	"E08000037": 202055,  // Gateshead
+
+	// the london boroughs
+	"E09000001": 9721,   // City of London
+	"E09000002": 212906, // Barking and Dagenham
+	"E09000003": 395896, // Barnet
+	"E09000004": 248287, // Bexley
+	"E09000005": 329771, // Brent
+	"E09000006": 332336, // Bromley
+	"E09000007": 270029, // Camden
+	"E09000008": 386710, // Croydon
+	"E09000009": 341806, // Ealing
+	"E09000010": 333794, // Enfield
+	"E09000011": 287942, // Greenwich
+	"E09000012": 290841, // Hackney and City of London
+	"E09000013": 185143, // Hammersmith and Fulham
+	"E09000014": 268647, // Haringey
+	"E09000015": 251160, // Harrow
+	"E09000016": 259552, // Havering
+	"E09000017": 306870, // Hillingdon
+	"E09000018": 271523, // Hounslow
+	"E09000019": 242467, // Islington
+	"E09000020": 156129, // Kensington and Chelsea
+	"E09000021": 177507, // Kingston upon Thames
+	"E09000022": 326034, // Lambeth
+	"E09000023": 305842, // Lewisham
+	"E09000024": 206548, // Merton
+	"E09000025": 353134, // Newham
+	"E09000026": 305222, // Redbridge
+	"E09000027": 198019, // Richmond upon Thames
+	"E09000028": 318830, // Southwark
+	"E09000029": 206349, // Sutton
+	"E09000030": 324745, // Tower Hamlets
+	"E09000031": 276983, // Waltham Forest
+	"E09000032": 329677, // Wandsworth
+	"E09000033": 261317, // Westminster
+
+	// the scottish council areas
+	"S12000005": 51540,  // Clackmannanshire
+	"S12000006": 148860, // Dumfries and Galloway
+	"S12000008": 122010, // East Ayrshire
+	"S12000010": 107090, // East Lothian
+	"S12000011": 95530,  // East Renfrewshire
+	"S12000013": 26720,  // Na h-Eileanan Siar
+	"S12000014": 160890, // Falkirk
+	"S12000017": 235540, // Highland
+	"S12000018": 77800,  // Inverclyde
+	"S12000019": 92460,  // Midlothian
+	"S12000020": 95520,  // Moray
+	"S12000021": 134740, // North Ayrshire
+	"S12000023": 22270,  // Orkney Islands
+	"S12000026": 115510, // Scottish Borders
+	"S12000027": 22920,  // Shetland Islands
+	"S12000028": 112610, // South Ayrshire
+	"S12000029": 320530, // South Lanarkshire
+	"S12000030": 94330,  // Stirling
+	"S12000033": 228670, // Aberdeen City
+	"S12000034": 261210, // Aberdeenshire
+	"S12000035": 85870,  // Argyll and Bute
+	"S12000036": 524930, // City of Edinburgh
+	"S12000038": 179100, // Renfrewshire
+	"S12000039": 88930,  // West Dunbartonshire
+	"S12000040": 183100, // West Lothian
+	"S12000041": 116200, // Angus
+	"S12000042": 149320, // Dundee City
+	"S12000045": 108640, // East Dunbartonshire
+	"S12000047": 373550, // Fife
+	"S12000048": 151950, // Perth and Kinross
+	"S12000049": 633120, // Glasgow City
+	"S12000050": 341370, // North Lanarkshire
+
+	// the welsh unitary authorities
+	"W06000001": 70043,  // Isle of Anglesey
+	"W06000002": 124560, // Gwynedd
+	"W06000003": 117935, // Conwy
+	"W06000004": 95696,  // Denbighshire
+	"W06000005": 156100, // Flintshire
+	"W06000006": 136126, // Wrexham
+	"W06000008": 72695,  // Ceredigion
+	"W06000009": 125818, // Pembrokeshire
+	"W06000010": 188771, // Carmarthenshire
+	"W06000011": 246993, // Swansea
+	"W06000012": 143315, // Neath Port Talbot
+	"W06000013": 147049, // Bridgend
+	"W06000014": 135295, // Vale of Glamorgan
+	"W06000015": 366903, // Cardiff
+	"W06000016": 241264, // Rhondda Cynon Taf
+	"W06000018": 181731, // Caerphilly
+	"W06000019": 70020,  // Blaenau Gwent
+	"W06000020": 94832,  // Torfaen
+	"W06000021": 94590,  // Monmouthshire
+	"W06000022": 154676, // Newport
+	"W06000023": 132435, // Powys
+	"W06000024": 60326,  // Merthyr Tydfil
+
+	// the northern irish local government districts
+	"N09000001": 143504, // Antrim and Newtownabbey
+	"N09000002": 216205, // Armagh City, Banbridge and Craigavon
+	"N09000003": 343542, // Belfast
+	"N09000004": 144838, // Causeway Coast and Glens
+	"N09000005": 151284, // Derry City and Strabane
+	"N09000006": 117397, // Fermanagh and Omagh
+	"N09000007": 146002, // Lisburn and Castlereagh
+	"N09000008": 139274, // Mid and East Antrim
+	"N09000009": 148528, // Mid Ulster
+	"N09000010": 181368, // Newry, Mourne and Down
+	"N09000011": 161725, // Ards and North Down
+}
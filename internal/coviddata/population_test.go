@@ -0,0 +1,80 @@
+package coviddata
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPopulations_Override(t *testing.T) {
+	populations := DefaultPopulations()
+
+	err := populations.Override(strings.NewReader("# code,population\nE06000001, 93663\n\nE92000001,57000000\n"))
+
+	if err != nil || populations["E06000001"] != 93663 || populations["E92000001"] != 57000000 {
+		t.Fatalf("Expected the overrides to be added, got err %v", err)
+	}
+
+	if midYearEstimates["E92000001"] != 56286961 {
+		t.Fatalf("Overriding the populations shouldn't change the built in estimates")
+	}
+}
+
+func TestPopulations_OverrideWithInvalidPopulation(t *testing.T) {
+	err := DefaultPopulations().Override(strings.NewReader("E06000001,lots\n"))
+
+	if err == nil {
+		t.Fatalf("Override() should return an error when the population isn't a number")
+	}
+}
+
+func TestPopulations_Per100k(t *testing.T) {
+	populations := Populations{"E06000001": 200000}
+
+	rate, err := populations.per100k(50, "E06000001")
+
+	if err != nil || rate != 25 {
+		t.Fatalf("Expected a rate of 25 per 100k but got %f and err %v", rate, err)
+	}
+}
+
+func TestPopulations_Per100kUnknownArea(t *testing.T) {
+	_, err := Populations{}.per100k(50, "E06000001")
+
+	if err == nil {
+		t.Fatalf("per100k() should return an error when the area's population isn't known")
+	}
+}
+
+func TestDefaultPopulations_CoverEveryAreaType(t *testing.T) {
+	codes := map[string]string{
+		"nhsRegion": "E40000008",
+		"county":    "E10000017",
+		"unitary":   "E06000060",
+		"district":  "E07000117",
+		"borough":   "E09000012",
+		"scotland":  "S12000049",
+		"wales":     "W06000011",
+		"ni":        "N09000003",
+	}
+
+	populations := DefaultPopulations()
+	for areaType, code := range codes {
+		if _, err := populations.per100k(1, code); err != nil {
+			t.Errorf("Expected a population for the %s %s, got %v", areaType, code, err)
+		}
+	}
+}
+
+func TestDefaultPopulations_CountiesAreTheirDistricts(t *testing.T) {
+	districts := []string{"E07000117", "E07000118", "E07000119", "E07000120", "E07000121", "E07000122",
+		"E07000123", "E07000124", "E07000125", "E07000126", "E07000127", "E07000128"}
+
+	total := 0
+	for _, code := range districts {
+		total += midYearEstimates[code]
+	}
+
+	if total != midYearEstimates["E10000017"] {
+		t.Fatalf("Expected Lancashire to be the %d people in its districts, got %d", total, midYearEstimates["E10000017"])
+	}
+}
@@ -0,0 +1,198 @@
+package coviddata
+
+import (
+	"context"
+	"covid-stats-cli/internal/barchart"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Renderer is what a query's figures are turned into
+type Renderer string
+
+const (
+	// RenderChart draws the text chart in the handler's style
+	RenderChart Renderer = "chart"
+	// RenderBarChart draws the chart as rows, e.g. to save as an image
+	RenderBarChart Renderer = "bar chart"
+	// RenderSeries is the figures behind the chart, for exporting. A day charted as a gap has no row
+	RenderSeries Renderer = "series"
+	// RenderSummary is the weekly totals and how they've changed
+	RenderSummary Renderer = "summary"
+)
+
+// Query is what to fetch and how to show it. The menu, the flags and the exports all build one, so
+// a new metric or setting only needs adding here and in Run
+type Query struct {
+	Metric Metric
+	// Area is the area to fetch, the handler's own when it's left empty
+	Area Area
+	// Compare fetches these areas to chart side by side instead of Area. Only charts and series can
+	// compare areas
+	Compare []Area
+	// PreviousDays is the date range, the days up to today
+	PreviousDays int
+	// DateBasis is whether the days are when the figures were published or when they happened, by
+	// publish date when it's left empty
+	DateBasis DateBasis
+	// RollingWindow aggregates each day with the days before it into an average, 0 for the daily
+	// figures
+	RollingWindow int
+	// Per100k transforms the figures into rates per 100,000 people living in the area
+	Per100k bool
+	// Render is each of the ways the figures are shown, e.g. a chart and its summary, which are all
+	// rendered from the same fetch
+	Render []Renderer
+}
+
+// Result is what a query rendered. Only the fields for the query's Renderers are filled in, and
+// Series has one per area
+type Result struct {
+	Chart    string
+	BarChart barchart.BarChart
+	Series   []Series
+	Summary  Summary
+}
+
+// Run fetches the figures the query asks for once, transforms them and renders them with each of
+// its renderers, so they all show the same figures. The query says what's fetched and how it's
+// transformed, the handler's own settings only how the charts are drawn, e.g. their style and width.
+// It stops fetching and returns ctx's error as soon as it's cancelled
+func (h Handler) Run(ctx context.Context, q Query) (Result, error) {
+	if err := q.validate(); err != nil {
+		return Result{}, err
+	}
+
+	if q.Area != (Area{}) && q.Area != h.api.area() {
+		h.api = h.api.forArea(q.Area)
+	}
+	if q.DateBasis == "" {
+		q.DateBasis = PublishDate
+	}
+	q.Metric = q.Metric.ByDate(q.DateBasis)
+
+	if len(q.Compare) > 0 {
+		return h.runComparison(ctx, q)
+	}
+
+	// the chart needs the extra days for its rolling average, and the summary the last fortnight
+	days := q.PreviousDays + q.extraDays()
+	fetchDays := days
+	if q.renders(RenderSummary) && fetchDays < q.Metric.summaryDays() {
+		fetchDays = q.Metric.summaryDays()
+	}
+
+	covidData, err := h.fetch(ctx, h.api, q.Metric, fetchDays)
+	if err != nil {
+		return Result{}, err
+	}
+	charted := covidData
+	if fetchDays > days {
+		charted = since(covidData, days)
+		if len(charted) == 0 {
+			return Result{}, fmt.Errorf("%w on %s for %s in the last %d days", ErrEmptyResponse,
+				strings.ToLower(q.Metric.Title), h.api.area(), days)
+		}
+	}
+
+	var result Result
+	for _, render := range q.Render {
+		switch render {
+		case RenderChart:
+			result.Chart, err = h.chart(q, charted)
+		case RenderBarChart:
+			result.BarChart, err = h.barChart(q, charted)
+		case RenderSeries:
+			var series Series
+			series, err = h.toSeries(q, h.api.area(), charted)
+			result.Series = []Series{series}
+		case RenderSummary:
+			result.Summary = h.summary(q, covidData)
+		}
+		if err != nil {
+			return Result{}, err
+		}
+	}
+
+	return result, nil
+}
+
+// runComparison fetches every area the query compares once and renders them side by side
+func (h Handler) runComparison(ctx context.Context, q Query) (Result, error) {
+	results, err := h.fetchAreas(ctx, q.Metric, q.PreviousDays+q.extraDays(), q.Compare)
+	if err != nil {
+		return Result{}, err
+	}
+
+	var result Result
+	for _, render := range q.Render {
+		switch render {
+		case RenderChart:
+			result.Chart, err = h.comparisonChart(q, results)
+		case RenderSeries:
+			result.Series, err = h.comparisonSeries(q, results)
+		}
+		if err != nil {
+			return Result{}, err
+		}
+	}
+
+	return result, nil
+}
+
+// validate is a *QueryError if the query can't be run whatever the api sends, so it's found before
+// fetching
+func (q Query) validate() error {
+	if q.RollingWindow < 0 {
+		return &QueryError{fmt.Sprintf("a rolling average can't be over %d days", q.RollingWindow)}
+	}
+	if q.Per100k && q.Metric.rate {
+		return &QueryError{fmt.Sprintf("the %s is already a rate so can't be shown per 100k people",
+			strings.ToLower(q.Metric.Title))}
+	}
+	for _, render := range q.Render {
+		if err := q.canRender(render); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// canRender is a *QueryError if the query can't be rendered as render
+func (q Query) canRender(render Renderer) error {
+	switch render {
+	case RenderChart, RenderSeries:
+		return nil
+	case RenderBarChart, RenderSummary:
+		if len(q.Compare) > 0 {
+			return &QueryError{fmt.Sprintf("a %s can't compare areas, only a chart or series can", render)}
+		}
+		return nil
+	}
+
+	return &QueryError{fmt.Sprintf("'%s' isn't something a query can be rendered as", render)}
+}
+
+func (q Query) renders(render Renderer) bool {
+	for _, r := range q.Render {
+		if r == render {
+			return true
+		}
+	}
+	return false
+}
+
+// since is the days of covidData in the last days, e.g. the charted ones when more were fetched for
+// the summary
+func since(covidData []data, days int) []data {
+	from := time.Now().Add(time.Duration(-days*24) * time.Hour)
+
+	var recent []data
+	for _, d := range covidData {
+		if isOnOrAfter(from, d.date) {
+			recent = append(recent, d)
+		}
+	}
+	return recent
+}
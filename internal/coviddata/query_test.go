@@ -0,0 +1,112 @@
+package coviddata
+
+import (
+	"context"
+	"covid-stats-cli/internal/logging"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestHandler_Run_UsesTheQuerysAreaAndSettings(t *testing.T) {
+	london := Area{Region, "London"}
+	oneDayAgo := time.Now().Add(time.Hour * -24)
+	twoDaysAgo := time.Now().Add(time.Hour * -48)
+	var fetched Area
+	mockApi := mockRestApi{mockArea: England, mockGetData: func(area Area, _ int) ([]data, error) {
+		fetched = area
+		return []data{
+			{date: oneDayAgo, values: map[string]float64{"cases": 6}},
+			{date: twoDaysAgo, values: map[string]float64{"cases": 2}},
+		}, nil
+	}}
+	handler := NewHandler(mockApi, logging.Discard())
+
+	result, err := handler.Run(context.Background(), Query{Metric: Cases, Area: london, PreviousDays: 2,
+		RollingWindow: 2, Render: []Renderer{RenderSeries}})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fetched != london {
+		t.Errorf("expected the query's area %s to be fetched, got %s", london, fetched)
+	}
+	if len(result.Series) != 1 || result.Series[0].RollingWindow != 2 || result.Series[0].Rows[1].RollingAverage != 4 {
+		t.Errorf("expected a series with the 2-day average the query asked for, got %+v", result.Series)
+	}
+}
+
+func TestHandler_Run_OnlyChartsAndSeriesCompareAreas(t *testing.T) {
+	handler := NewHandler(givenApiThatReturns(nil, nil), logging.Discard())
+
+	_, err := handler.Run(context.Background(), Query{Metric: Cases, PreviousDays: 7, Compare: []Area{England},
+		Render: []Renderer{RenderChart, RenderSummary}})
+
+	var queryErr *QueryError
+	if !errors.As(err, &queryErr) {
+		t.Fatalf("expected a QueryError saying a summary can't compare areas, got %v", err)
+	}
+}
+
+func TestHandler_Run_RatePer100kOfARateIsRejectedBeforeFetching(t *testing.T) {
+	fetched := false
+	mockApi := mockRestApi{mockGetData: func(_ Area, _ int) ([]data, error) {
+		fetched = true
+		return nil, nil
+	}}
+	handler := NewHandler(mockApi, logging.Discard())
+
+	for _, render := range []Renderer{RenderChart, RenderSeries} {
+		_, err := handler.Run(context.Background(), Query{Metric: PositivityRate, PreviousDays: 7, Per100k: true,
+			Render: []Renderer{render}})
+
+		var queryErr *QueryError
+		if !errors.As(err, &queryErr) || fetched {
+			t.Fatalf("expected a QueryError for the %s of a rate per 100k without fetching, got %v and fetched=%v",
+				render, err, fetched)
+		}
+	}
+}
+
+func TestHandler_Run_UnknownRenderer(t *testing.T) {
+	handler := NewHandler(givenApiThatReturns(nil, nil), logging.Discard())
+
+	_, err := handler.Run(context.Background(), Query{Metric: Cases, PreviousDays: 7, Render: []Renderer{"pie chart"}})
+
+	var queryErr *QueryError
+	if !errors.As(err, &queryErr) {
+		t.Fatalf("Run() should return a QueryError for a renderer it doesn't know, got %v", err)
+	}
+}
+
+func TestHandler_Run_RendersEverythingFromOneFetch(t *testing.T) {
+	fetches := 0
+	var fetchedDays int
+	mockApi := mockRestApi{mockArea: England, mockGetData: func(_ Area, previousDays int) ([]data, error) {
+		fetches++
+		fetchedDays = previousDays
+		var covidData []data
+		for day := 1; day <= previousDays; day++ {
+			covidData = append(covidData, data{date: time.Now().AddDate(0, 0, -day), values: map[string]float64{"cases": float64(day)}})
+		}
+		return covidData, nil
+	}}
+	handler := NewHandler(mockApi, logging.Discard())
+	handler.SetWidth(80)
+
+	result, err := handler.Run(context.Background(), Query{Metric: Cases, PreviousDays: 7,
+		Render: []Renderer{RenderChart, RenderBarChart, RenderSeries, RenderSummary}})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fetches != 1 || fetchedDays != summaryDays {
+		t.Fatalf("expected one fetch of the %d days the summary needs, got %d of %d days", summaryDays, fetches, fetchedDays)
+	}
+	if result.Chart == "" || len(result.BarChart.Bars()) != 7 || len(result.Series[0].Rows) != 7 {
+		t.Errorf("expected the chart, bar chart and series to have the 7 days asked for, got %+v", result)
+	}
+	if result.Summary.ThisWeek != 1+2+3+4+5+6+7 || result.Summary.LastWeek != 8+9+10+11+12+13+14 {
+		t.Errorf("expected a summary comparing the last fortnight, got %+v", result.Summary)
+	}
+}
@@ -1,96 +1,137 @@
 package coviddata
 
 import (
+	"context"
+	"covid-stats-cli/internal/logging"
 	"covid-stats-cli/internal/rest"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io/ioutil"
-	"strconv"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
 	"time"
 )
 
 type response struct {
-	Data []struct {
-		Date *string
-		Cases *int
-		Deaths *int
+	Data       []responseData
+	Pagination struct {
+		Next *string
 	}
 }
 
+// each entry has a date plus a number, or null, for every metric asked for
+type responseData map[string]interface{}
+
+// guards against an api that keeps handing back a next page
+const maxPages = 100
+
 type restApi interface {
-	getData(previousDays int) ([]data, error)
+	getData(ctx context.Context, previousDays int, metrics []Metric) ([]data, error)
+	area() Area
+	forArea(area Area) restApi
+	// lastUpdated is when the api last updated the data it's sent, or zero if it hasn't said
+	lastUpdated() time.Time
 }
 
 type restApiImpl struct {
-	url string
-	client rest.Client
+	url          string
+	selectedArea Area
+	client       rest.Client
+	updates      *updateTracker
+	log          *logging.Logger
 }
 
-func NewCovidDataRestApi(url string, client rest.Client) restApi {
-	return restApiImpl{url, client}
+// updateTracker remembers the latest Last-Modified header of the api's responses. It's shared by
+// the copies of the api for other areas, which can fetch at the same time
+type updateTracker struct {
+	sync.Mutex
+	latest time.Time
 }
 
-func (api restApiImpl) getData(previousDays int) ([]data, error) {
-	resp, err := api.client.Get(api.url)
+func (u *updateTracker) seen(lastModified string) {
+	updated, err := http.ParseTime(lastModified)
 	if err != nil {
-		return nil, err
+		return
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != 200 {
-		return nil, errors.New("Received non-200 status code " + strconv.Itoa(resp.StatusCode))
+	u.Lock()
+	defer u.Unlock()
+	if updated.After(u.latest) {
+		u.latest = updated
 	}
+}
 
-	bytes, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
+// NewCovidDataRestApi fetches from the api at url with client. Days missing a figure are collected
+// by log, to be warned about once the chart's drawn
+func NewCovidDataRestApi(url string, area Area, client rest.Client, log *logging.Logger) restApi {
+	return restApiImpl{url, area, client, &updateTracker{}, log}
+}
 
-	var response response
-	err = json.Unmarshal(bytes, &response)
+func (api restApiImpl) area() Area {
+	return api.selectedArea
+}
+
+func (api restApiImpl) forArea(area Area) restApi {
+	return restApiImpl{api.url, area, api.client, api.updates, api.log}
+}
+
+func (api restApiImpl) lastUpdated() time.Time {
+	api.updates.Lock()
+	defer api.updates.Unlock()
+	return api.updates.latest
+}
+
+// requestUrl asks the api for the days on or after from. The api only has a strictly greater than
+// filter, so it's given the day before
+func (api restApiImpl) requestUrl(from time.Time, metrics []Metric) string {
+	return api.url + "?filters=" + api.selectedArea.filter() + ";date" + url.QueryEscape(">") +
+		from.AddDate(0, 0, -1).Format("2006-01-02") + "&structure=" + structure(metrics)
+}
+
+func (api restApiImpl) getData(ctx context.Context, previousDays int, metrics []Metric) ([]data, error) {
+	from := time.Now().Add(time.Duration(-previousDays*24) * time.Hour)
+
+	entries, err := api.getAllPages(ctx, api.requestUrl(from, metrics))
 	if err != nil {
 		return nil, err
 	}
 
-	if len(response.Data) == 0 {
-		return nil, errors.New(fmt.Sprintf("response %+v is empty", response))
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("%w for %s in the last %d days", ErrEmptyResponse, api.selectedArea, previousDays)
 	}
+	api.log.Debugf("the api sent %d days of figures for %s", len(entries), api.selectedArea)
 
-	from := time.Now().Add(time.Duration(-previousDays*24) * time.Hour)
 	var covidData []data
-	for _, responseData := range response.Data {
-		if responseData.Date == nil {
-			return nil, errors.New("the covid data api is returning entries with no specified date")
+	for _, responseData := range entries {
+		rawDate, ok := responseData["date"].(string)
+		if !ok {
+			return nil, ErrMissingDate
 		}
 
-		date, err := time.Parse("2006-01-02", *responseData.Date)
+		date, err := time.Parse("2006-01-02", rawDate)
 		if err != nil {
-			return nil, err
+			return nil, &DecodeError{Url: api.url, Err: err}
 		}
 
 		if isOnOrAfter(from, date) && !isSameDay(time.Now(), date) {
-			var cases int
-			var deaths int
-
-			if responseData.Cases == nil {
-				fmt.Printf("WARNING: the api has no case data for %v. This might skew the results.", date)
-				cases = 0
-			} else {
-				cases = *responseData.Cases
-			}
-
-			if responseData.Deaths == nil {
-				fmt.Printf("WARNING: the api has no death data for %v. This might skew the results.", date)
-				deaths = 0
-			} else {
-				deaths = *responseData.Deaths
+			values := make(map[string]float64)
+			for _, metric := range metrics {
+				// a null or missing figure means there's no data for that day, which isn't the same as 0
+				if value, ok := responseData[metric.Name].(float64); ok {
+					values[metric.Name] = value
+				} else {
+					api.log.Collect(fmt.Sprintf("the api has no %s figures for %s on these days",
+						strings.ToLower(metric.Title), api.selectedArea), date.Format("02/01"))
+				}
 			}
 
+			areaCode, _ := responseData["areaCode"].(string)
 			covidData = append(covidData, data{
-				date:   date,
-				cases:  cases,
-				deaths: deaths,
+				date:     date,
+				areaCode: areaCode,
+				values:   values,
 			})
 		}
 	}
@@ -98,6 +139,87 @@ func (api restApiImpl) getData(previousDays int) ([]data, error) {
 	return covidData, nil
 }
 
+// getAllPages follows the api's pagination.next links until there are no pages left
+func (api restApiImpl) getAllPages(ctx context.Context, pageUrl string) ([]responseData, error) {
+	var entries []responseData
+	visited := make(map[string]bool)
+
+	for pageUrl != "" {
+		if visited[pageUrl] || len(visited) == maxPages {
+			return nil, fmt.Errorf("the covid data api's pagination doesn't end, stopped at %s", pageUrl)
+		}
+		visited[pageUrl] = true
+
+		page, err := api.getPage(ctx, pageUrl)
+		if err != nil {
+			return nil, err
+		}
+		if page == nil {
+			break
+		}
+
+		entries = append(entries, page.Data...)
+
+		pageUrl = ""
+		if page.Pagination.Next != nil && *page.Pagination.Next != "" {
+			pageUrl, err = api.resolve(*page.Pagination.Next)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return entries, nil
+}
+
+// getPage returns nil when the api has nothing (more) to send. A 304 is answered with the cached
+// copy by the caching client, so one that gets this far means there's no copy to show and is an error
+func (api restApiImpl) getPage(ctx context.Context, pageUrl string) (*response, error) {
+	resp, err := api.client.Get(ctx, pageUrl, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// 204 means there's no data for the filters
+	if resp.StatusCode == http.StatusNoContent {
+		return nil, nil
+	}
+
+	if resp.StatusCode != 200 {
+		return nil, &StatusError{StatusCode: resp.StatusCode, Url: pageUrl}
+	}
+
+	bytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	api.updates.seen(resp.Header.Get("Last-Modified"))
+
+	var response response
+	err = json.Unmarshal(bytes, &response)
+	if err != nil {
+		return nil, &DecodeError{Url: pageUrl, Err: err}
+	}
+
+	return &response, nil
+}
+
+// the api's next links are relative, e.g. /v1/data?filters=...&page=2
+func (api restApiImpl) resolve(next string) (string, error) {
+	base, err := url.Parse(api.url)
+	if err != nil {
+		return "", err
+	}
+
+	nextUrl, err := url.Parse(next)
+	if err != nil {
+		return "", err
+	}
+
+	return base.ResolveReference(nextUrl).String(), nil
+}
+
 func isSameDay(t1 time.Time, t2 time.Time) bool {
 	return t1.Year() == t2.Year() && t1.YearDay() == t2.YearDay()
 }
@@ -108,4 +230,4 @@ func isOnOrAfter(t1 time.Time, t2 time.Time) bool {
 	}
 
 	return t1.Year() == t2.Year() && t1.YearDay() <= t2.YearDay()
-}
\ No newline at end of file
+}
@@ -2,9 +2,15 @@ package coviddata
 
 import (
 	"bytes"
+	"context"
+	"covid-stats-cli/internal/logging"
+	"encoding/json"
+	"errors"
 	"io/ioutil"
 	"net/http"
+	"reflect"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 )
@@ -13,28 +19,143 @@ type mockRestClient struct {
 	response http.Response
 }
 
-func (c mockRestClient) Get(_ string) (resp *http.Response, err error) {
+func (c mockRestClient) Get(_ context.Context, _ string, _ http.Header) (resp *http.Response, err error) {
 	return &c.response, nil
 }
 
+// pagedRestClient hands back its responses in order and records the urls it was asked for
+type pagedRestClient struct {
+	responses []http.Response
+	urls      *[]string
+}
+
+func (c pagedRestClient) Get(_ context.Context, url string, _ http.Header) (resp *http.Response, err error) {
+	*c.urls = append(*c.urls, url)
+	response := c.responses[len(*c.urls)-1]
+	return &response, nil
+}
+
+func jsonResponse(body string) http.Response {
+	return http.Response{
+		StatusCode: 200,
+		Body:       ioutil.NopCloser(bytes.NewBufferString(body)),
+	}
+}
+
+func TestRestApi_GetData_RequestsOnlyTheWindow(t *testing.T) {
+	var urls []string
+	client := pagedRestClient{[]http.Response{{StatusCode: 204, Body: ioutil.NopCloser(bytes.NewBufferString(""))}}, &urls}
+	api := NewCovidDataRestApi("http://www.amireallyreal.com/v1/data", England, client, logging.Discard())
+
+	api.getData(context.Background(), 3, []Metric{Cases, Deaths})
+
+	dayBeforeWindow := time.Now().Add(time.Hour * -96).Format("2006-01-02")
+	if len(urls) != 1 || !strings.Contains(urls[0], "?filters=areaType=nation;areaName=england;date%3E"+dayBeforeWindow+"&") {
+		t.Fatalf("Expected a request filtered to dates after %s, got %v", dayBeforeWindow, urls)
+	}
+}
+
+func TestRestApi_GetData_FollowsPagination(t *testing.T) {
+	oneDayAgo := data{date: time.Now().Add(time.Hour * -24), values: map[string]float64{"cases": 81, "deaths": 10}}
+	twoDaysAgo := data{date: time.Now().Add(time.Hour * -48), values: map[string]float64{"cases": 400, "deaths": 4}}
+
+	var urls []string
+	client := pagedRestClient{[]http.Response{
+		jsonResponse("{\"data\":[" + asJson(oneDayAgo) + "],\"pagination\":{\"next\":\"/v1/data?page=2\"}}"),
+		jsonResponse("{\"data\":[" + asJson(twoDaysAgo) + "],\"pagination\":{\"next\":null}}"),
+	}, &urls}
+	api := NewCovidDataRestApi("http://www.amireallyreal.com/v1/data", England, client, logging.Discard())
+
+	actual, err := api.getData(context.Background(), 3, []Metric{Cases, Deaths})
+
+	if err != nil || !deepEqual(actual, []data{oneDayAgo, twoDaysAgo}) {
+		t.Fatalf("Expected data from both pages, got %+v and err %v", actual, err)
+	}
+
+	if len(urls) != 2 || urls[1] != "http://www.amireallyreal.com/v1/data?page=2" {
+		t.Fatalf("Expected the second page to be requested, got %v", urls)
+	}
+}
+
+func TestRestApi_GetData_StopsPaginatingOnNoContent(t *testing.T) {
+	oneDayAgo := data{date: time.Now().Add(time.Hour * -24), values: map[string]float64{"cases": 81, "deaths": 10}}
+
+	var urls []string
+	client := pagedRestClient{[]http.Response{
+		jsonResponse("{\"data\":[" + asJson(oneDayAgo) + "],\"pagination\":{\"next\":\"/v1/data?page=2\"}}"),
+		{StatusCode: 204, Body: ioutil.NopCloser(bytes.NewBufferString(""))},
+	}, &urls}
+	api := NewCovidDataRestApi("http://www.amireallyreal.com/v1/data", England, client, logging.Discard())
+
+	actual, err := api.getData(context.Background(), 3, []Metric{Cases, Deaths})
+
+	if err != nil || !deepEqual(actual, []data{oneDayAgo}) {
+		t.Fatalf("Expected data from the first page, got %+v and err %v", actual, err)
+	}
+}
+
+func TestRestApi_GetData_NotModifiedPartWayThroughIsAnError(t *testing.T) {
+	oneDayAgo := data{date: time.Now().Add(time.Hour * -24), values: map[string]float64{"cases": 81, "deaths": 10}}
+
+	var urls []string
+	client := pagedRestClient{[]http.Response{
+		jsonResponse("{\"data\":[" + asJson(oneDayAgo) + "],\"pagination\":{\"next\":\"/v1/data?page=2\"}}"),
+		{StatusCode: 304, Body: ioutil.NopCloser(bytes.NewBufferString(""))},
+	}, &urls}
+	api := NewCovidDataRestApi("http://www.amireallyreal.com/v1/data", England, client, logging.Discard())
+
+	actual, err := api.getData(context.Background(), 3, []Metric{Cases, Deaths})
+
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != 304 || len(actual) > 0 {
+		t.Fatalf("Expected a 304 StatusError rather than the first page alone, got %+v and err %v", actual, err)
+	}
+}
+
+func TestRestApi_GetData_NoContent(t *testing.T) {
+	var urls []string
+	client := pagedRestClient{[]http.Response{{StatusCode: 204, Body: ioutil.NopCloser(bytes.NewBufferString(""))}}, &urls}
+	api := NewCovidDataRestApi("http://www.amireallyreal.com/v1/data", England, client, logging.Discard())
+
+	data, err := api.getData(context.Background(), 3, []Metric{Cases, Deaths})
+
+	if !errors.Is(err, ErrEmptyResponse) || len(data) > 0 {
+		t.Fatalf("Expected err '%v' to be ErrEmptyResponse and data (len=%d) to be empty\n", err, len(data))
+	}
+}
+
+func TestRestApi_GetData_PaginationThatNeverEnds(t *testing.T) {
+	oneDayAgo := data{date: time.Now().Add(time.Hour * -24), values: map[string]float64{"cases": 81, "deaths": 10}}
+
+	var urls []string
+	client := pagedRestClient{[]http.Response{
+		jsonResponse("{\"data\":[" + asJson(oneDayAgo) + "],\"pagination\":{\"next\":\"/v1/data?page=2\"}}"),
+		jsonResponse("{\"data\":[" + asJson(oneDayAgo) + "],\"pagination\":{\"next\":\"/v1/data?page=2\"}}"),
+	}, &urls}
+	api := NewCovidDataRestApi("http://www.amireallyreal.com/v1/data", England, client, logging.Discard())
+
+	_, err := api.getData(context.Background(), 3, []Metric{Cases, Deaths})
+
+	if err == nil || len(urls) != 2 {
+		t.Fatalf("Expected an error after the same page was linked twice, got err %v after %v", err, urls)
+	}
+}
+
 func TestRestApi_GetData_ClientReturnsNon200StatusCode(t *testing.T) {
 	url := "http://www.amireallyreal.com"
 	client := mockRestClient{
 		http.Response{
 			StatusCode: 500,
-			Body: ioutil.NopCloser(bytes.NewBufferString("Hello World")),
+			Body:       ioutil.NopCloser(bytes.NewBufferString("Hello World")),
 		},
 	}
-	api := NewCovidDataRestApi(url, client)
+	api := NewCovidDataRestApi(url, England, client, logging.Discard())
 
-	data, err := api.getData(5)
+	data, err := api.getData(context.Background(), 5, []Metric{Cases, Deaths})
 
-	expectedErrorMsg := "Received non-200 status code 500"
-	if err == nil || (expectedErrorMsg != err.Error() || len(data) > 0) {
-		t.Fatalf("Expected err '%v' to be '%v' and data (len=%d) to be empty\n",
-			err,
-			expectedErrorMsg,
-			len(data))
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != 500 || !statusErr.Temporary() || len(data) > 0 {
+		t.Fatalf("Expected err '%v' to be a StatusError for a 500 and data (len=%d) to be empty\n", err, len(data))
 	}
 }
 
@@ -43,19 +164,15 @@ func TestRestApi_GetData_ResponseHasDataWithMissingDate(t *testing.T) {
 	client := mockRestClient{
 		http.Response{
 			StatusCode: 200,
-			Body: ioutil.NopCloser(bytes.NewBufferString("{\"data\":[{\"deaths\": 5, \"cases\": 500}]}")),
+			Body:       ioutil.NopCloser(bytes.NewBufferString("{\"data\":[{\"deaths\": 5, \"cases\": 500}]}")),
 		},
 	}
-	api := NewCovidDataRestApi(url, client)
+	api := NewCovidDataRestApi(url, England, client, logging.Discard())
 
-	data, err := api.getData(5)
+	data, err := api.getData(context.Background(), 5, []Metric{Cases, Deaths})
 
-	expectedErrorMsg := "the covid data api is returning entries with no specified date"
-	if err == nil || (expectedErrorMsg != err.Error() || len(data) > 0) {
-		t.Fatalf("Expected err '%v' to be '%v' and data (len=%d) to be empty\n",
-			err,
-			expectedErrorMsg,
-			len(data))
+	if !errors.Is(err, ErrMissingDate) || len(data) > 0 {
+		t.Fatalf("Expected err '%v' to be ErrMissingDate and data (len=%d) to be empty\n", err, len(data))
 	}
 }
 
@@ -65,15 +182,15 @@ func TestRestApi_GetData_ResponseHasDataWithMissingCases(t *testing.T) {
 	client := mockRestClient{
 		http.Response{
 			StatusCode: 200,
-			Body: ioutil.NopCloser(bytes.NewBufferString("{\"data\":[{\"date\":\""+yesterday+"\",\"deaths\":81}]}")),
+			Body:       ioutil.NopCloser(bytes.NewBufferString("{\"data\":[{\"date\":\"" + yesterday + "\",\"deaths\":81}]}")),
 		},
 	}
-	api := NewCovidDataRestApi(url, client)
+	api := NewCovidDataRestApi(url, England, client, logging.Discard())
 
-	data, err := api.getData(5)
+	data, err := api.getData(context.Background(), 5, []Metric{Cases, Deaths})
 
-	if err != nil || data[0].cases != 0 {
-		t.Fatalf("Expected err %v to be nil and cases %v to be 0", err, data[0].cases)
+	if _, ok := data[0].value(Cases); err != nil || ok {
+		t.Fatalf("Expected err %v to be nil and there to be no cases in %+v", err, data[0])
 	}
 }
 
@@ -83,15 +200,35 @@ func TestRestApi_GetData_ResponseHasDataWithMissingDeaths(t *testing.T) {
 	client := mockRestClient{
 		http.Response{
 			StatusCode: 200,
-			Body: ioutil.NopCloser(bytes.NewBufferString("{\"data\":[{\"date\":\""+yesterday+"\",\"cases\":81}]}")),
+			Body:       ioutil.NopCloser(bytes.NewBufferString("{\"data\":[{\"date\":\"" + yesterday + "\",\"cases\":81}]}")),
 		},
 	}
-	api := NewCovidDataRestApi(url, client)
+	api := NewCovidDataRestApi(url, England, client, logging.Discard())
 
-	data, err := api.getData(5)
+	data, err := api.getData(context.Background(), 5, []Metric{Cases, Deaths})
 
-	if err != nil || data[0].deaths != 0 {
-		t.Fatalf("Expected err %v to be nil and deaths %v to be 0", err, data[0].deaths)
+	if _, ok := data[0].value(Deaths); err != nil || ok {
+		t.Fatalf("Expected err %v to be nil and there to be no deaths in %+v", err, data[0])
+	}
+}
+
+func TestRestApi_GetData_OnlyKeepsTheMetricsAskedFor(t *testing.T) {
+	yesterday := time.Now().Add(time.Hour * -24).Format("2006-01-02")
+	url := "http://www.amireallyreal.com"
+	client := mockRestClient{
+		http.Response{
+			StatusCode: 200,
+			Body: ioutil.NopCloser(bytes.NewBufferString("{\"data\":[{\"date\":\"" + yesterday +
+				"\",\"positivity\":5.4,\"hospital\":null,\"cases\":81}]}")),
+		},
+	}
+	api := NewCovidDataRestApi(url, England, client, logging.Discard())
+
+	data, err := api.getData(context.Background(), 5, []Metric{PositivityRate, HospitalCases})
+
+	expected := map[string]float64{"positivity": 5.4}
+	if err != nil || len(data) != 1 || !reflect.DeepEqual(data[0].values, expected) {
+		t.Fatalf("Expected err %v to be nil and values %+v to be %+v", err, data, expected)
 	}
 }
 
@@ -100,19 +237,15 @@ func TestRestApi_GetData_ResponseHasNoData(t *testing.T) {
 	client := mockRestClient{
 		http.Response{
 			StatusCode: 200,
-			Body: ioutil.NopCloser(bytes.NewBufferString("{\"data\":[]}")),
+			Body:       ioutil.NopCloser(bytes.NewBufferString("{\"data\":[]}")),
 		},
 	}
-	api := NewCovidDataRestApi(url, client)
+	api := NewCovidDataRestApi(url, England, client, logging.Discard())
 
-	data, err := api.getData(5)
+	data, err := api.getData(context.Background(), 5, []Metric{Cases, Deaths})
 
-	expectedErrorMsg := "response {Data:[]} is empty"
-	if err == nil || (expectedErrorMsg != err.Error() || len(data) > 0) {
-		t.Fatalf("Expected err '%v' to be '%v' and data (len=%d) to be empty\n",
-			err,
-			expectedErrorMsg,
-			len(data))
+	if !errors.Is(err, ErrEmptyResponse) || len(data) > 0 {
+		t.Fatalf("Expected err '%v' to be ErrEmptyResponse and data (len=%d) to be empty\n", err, len(data))
 	}
 }
 
@@ -121,19 +254,15 @@ func TestRestApi_GetData_EmptyJsonResponse(t *testing.T) {
 	client := mockRestClient{
 		http.Response{
 			StatusCode: 200,
-			Body: ioutil.NopCloser(bytes.NewBufferString("{}")),
+			Body:       ioutil.NopCloser(bytes.NewBufferString("{}")),
 		},
 	}
-	api := NewCovidDataRestApi(url, client)
+	api := NewCovidDataRestApi(url, England, client, logging.Discard())
 
-	data, err := api.getData(5)
+	data, err := api.getData(context.Background(), 5, []Metric{Cases, Deaths})
 
-	expectedErrorMsg := "response {Data:[]} is empty"
-	if err == nil || (expectedErrorMsg != err.Error() || len(data) > 0) {
-		t.Fatalf("Expected err '%v' to be '%v' and data (len=%d) to be empty\n",
-			err,
-			expectedErrorMsg,
-			len(data))
+	if !errors.Is(err, ErrEmptyResponse) || len(data) > 0 {
+		t.Fatalf("Expected err '%v' to be ErrEmptyResponse and data (len=%d) to be empty\n", err, len(data))
 	}
 }
 
@@ -142,19 +271,17 @@ func TestRestApi_GetData_MalformedJsonResponse(t *testing.T) {
 	client := mockRestClient{
 		http.Response{
 			StatusCode: 200,
-			Body: ioutil.NopCloser(bytes.NewBufferString("[]")),// starting a json doc with '[]' is invalid syntax
+			Body:       ioutil.NopCloser(bytes.NewBufferString("[]")), // starting a json doc with '[]' is invalid syntax
 		},
 	}
-	api := NewCovidDataRestApi(url, client)
+	api := NewCovidDataRestApi(url, England, client, logging.Discard())
 
-	data, err := api.getData(5)
+	data, err := api.getData(context.Background(), 5, []Metric{Cases, Deaths})
 
-	expectedErrorMsg := "json: cannot unmarshal array into Go value of type coviddata.response"
-	if err == nil || (expectedErrorMsg != err.Error() || len(data) > 0) {
-		t.Fatalf("Expected err '%v' to be '%v' and data (len=%d) to be empty\n",
-			err,
-			expectedErrorMsg,
-			len(data))
+	var decodeErr *DecodeError
+	var typeErr *json.UnmarshalTypeError
+	if !errors.As(err, &decodeErr) || !errors.As(err, &typeErr) || len(data) > 0 {
+		t.Fatalf("Expected err '%v' to be a DecodeError wrapping the json error and data (len=%d) to be empty\n", err, len(data))
 	}
 }
 
@@ -171,18 +298,20 @@ func TestRestApi_GetData_FiltersOutDataWithTodaysDate(t *testing.T) {
 	client := mockRestClient{
 		http.Response{
 			StatusCode: 200,
-			Body: ioutil.NopCloser(bytes.NewBufferString(response)),
+			Body:       ioutil.NopCloser(bytes.NewBufferString(response)),
 		},
 	}
-	api := NewCovidDataRestApi(url, client)
+	api := NewCovidDataRestApi(url, England, client, logging.Discard())
 
-	data, _ := api.getData(1)
+	data, _ := api.getData(context.Background(), 1, []Metric{Cases, Deaths})
 
 	if len(data) != 1 {
 		t.Fatalf("Expected data %+v to have a length of 1 (today's should be filtered out)", data)
 	}
 
-	if !isSameDay(data[0].date, yesterday) || data[0].cases != 81 || data[0].deaths != 10 {
+	cases, _ := data[0].value(Cases)
+	deaths, _ := data[0].value(Deaths)
+	if !isSameDay(data[0].date, yesterday) || cases != 81 || deaths != 10 {
 		t.Fatalf("data %+v does not have the expected fields", data)
 	}
 }
@@ -192,11 +321,11 @@ func TestRestApi_GetData(t *testing.T) {
 
 	today := time.Now()
 
-	fourDaysAgo := data{date: today.Add(time.Hour * -96), cases: 1000, deaths: 50}
-	threeDaysAgo := data{date: today.Add(time.Hour * -72), cases: 400, deaths: 4}
-	twoDaysAgo := data{date: today.Add(time.Hour * -48), cases: 400, deaths: 4}
-	oneDayAgo := data{date: today.Add(time.Hour * -24), cases: 81, deaths: 10}
-	dataForToday := data{date: today, cases: 555, deaths: 8}
+	fourDaysAgo := data{date: today.Add(time.Hour * -96), values: map[string]float64{"cases": 1000, "deaths": 50}}
+	threeDaysAgo := data{date: today.Add(time.Hour * -72), values: map[string]float64{"cases": 400, "deaths": 4}}
+	twoDaysAgo := data{date: today.Add(time.Hour * -48), values: map[string]float64{"cases": 400, "deaths": 4}}
+	oneDayAgo := data{date: today.Add(time.Hour * -24), values: map[string]float64{"cases": 81, "deaths": 10}}
+	dataForToday := data{date: today, values: map[string]float64{"cases": 555, "deaths": 8}}
 
 	response := "{\"data\":["
 	response += asJson(fourDaysAgo) + ","
@@ -208,12 +337,12 @@ func TestRestApi_GetData(t *testing.T) {
 	client := mockRestClient{
 		http.Response{
 			StatusCode: 200,
-			Body: ioutil.NopCloser(bytes.NewBufferString(response)),
+			Body:       ioutil.NopCloser(bytes.NewBufferString(response)),
 		},
 	}
-	api := NewCovidDataRestApi(url, client)
+	api := NewCovidDataRestApi(url, England, client, logging.Discard())
 
-	actual, _ := api.getData(3)
+	actual, _ := api.getData(context.Background(), 3, []Metric{Cases, Deaths})
 
 	expected := make([]data, 0)
 	expected = append(expected, oneDayAgo)
@@ -241,7 +370,7 @@ func deepEqual(a []data, b []data) bool {
 
 func contains(dd []data, d data) bool {
 	for _, data := range dd {
-		if isSameDay(data.date, d.date) && data.deaths == d.deaths && data.cases == d.cases {
+		if isSameDay(data.date, d.date) && reflect.DeepEqual(data.values, d.values) {
 			return true
 		}
 	}
@@ -251,10 +380,104 @@ func contains(dd []data, d data) bool {
 
 func asJson(data data) string {
 	asJson := "{"
-	asJson += "\"deaths\":" + strconv.Itoa(data.deaths) + ","
-	asJson += "\"cases\":" + strconv.Itoa(data.cases) + ","
+	for name, value := range data.values {
+		asJson += "\"" + name + "\":" + strconv.FormatFloat(value, 'f', -1, 64) + ","
+	}
 	asJson += "\"date\":\"" + data.date.Format("2006-01-02") + "\""
 	asJson += "}"
 
 	return asJson
 }
+
+func TestRestApi_LastUpdated_IsTheLatestLastModifiedOfAnyArea(t *testing.T) {
+	oneDayAgo := data{date: time.Now().Add(time.Hour * -24), values: map[string]float64{"cases": 81}}
+	older, newer := jsonResponse("{\"data\":["+asJson(oneDayAgo)+"]}"), jsonResponse("{\"data\":["+asJson(oneDayAgo)+"]}")
+	older.Header = http.Header{"Last-Modified": {"Sun, 10 Jan 2021 15:02:11 GMT"}}
+	newer.Header = http.Header{"Last-Modified": {"Mon, 11 Jan 2021 15:10:00 GMT"}}
+
+	var urls []string
+	api := NewCovidDataRestApi("http://www.amireallyreal.com/v1/data", England, pagedRestClient{[]http.Response{newer, older}, &urls}, logging.Discard())
+	london := api.forArea(Area{Region, "London"})
+
+	if _, err := api.getData(context.Background(), 3, []Metric{Cases}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := london.getData(context.Background(), 3, []Metric{Cases}); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := time.Date(2021, 1, 11, 15, 10, 0, 0, time.UTC)
+	if !api.lastUpdated().Equal(expected) || !london.lastUpdated().Equal(expected) {
+		t.Fatalf("Expected both areas to be last updated at %v, got %v and %v", expected, api.lastUpdated(), london.lastUpdated())
+	}
+}
+
+// cancellableRestClient fails the way an http client does once the request's context is cancelled
+type cancellableRestClient struct{}
+
+func (cancellableRestClient) Get(ctx context.Context, _ string, _ http.Header) (resp *http.Response, err error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestHandler_Run_ComparisonChart_StopsWhenCancelled(t *testing.T) {
+	api := NewCovidDataRestApi("http://www.amireallyreal.com/v1/data", England, cancellableRestClient{}, logging.Discard())
+	handler := NewHandler(api, logging.Discard())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := handler.Run(ctx, Query{Metric: Cases, PreviousDays: 7, Compare: []Area{England, {Region, "London"}},
+		Render: []Renderer{RenderChart}})
+
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected the fetch to be cancelled, got %v", err)
+	}
+}
+
+func TestRestApi_GetData_ResponseHasAnUnreadableDate(t *testing.T) {
+	client := mockRestClient{jsonResponse("{\"data\":[{\"date\":\"10/01/2021\",\"cases\":500}]}")}
+	api := NewCovidDataRestApi("http://www.amireallyreal.com", England, client, logging.Discard())
+
+	_, err := api.getData(context.Background(), 5, []Metric{Cases})
+
+	var decodeErr *DecodeError
+	if !errors.As(err, &decodeErr) {
+		t.Fatalf("Expected a DecodeError, got %v", err)
+	}
+}
+
+func TestHandler_Run_ComparisonChart_KeepsTheStatusError(t *testing.T) {
+	client := mockRestClient{http.Response{StatusCode: 429, Body: ioutil.NopCloser(bytes.NewBufferString(""))}}
+	handler := NewHandler(NewCovidDataRestApi("http://www.amireallyreal.com", England, client, logging.Discard()), logging.Discard())
+
+	_, err := handler.Run(context.Background(), Query{Metric: Cases, PreviousDays: 7, Compare: []Area{England, {Region, "London"}},
+		Render: []Renderer{RenderChart}})
+
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != 429 {
+		t.Fatalf("Expected the 429 StatusError, got %v", err)
+	}
+}
+
+func TestRestApi_GetData_CollectsTheDaysMissingAFigure(t *testing.T) {
+	oneDayAgo := data{date: time.Now().Add(time.Hour * -24), values: map[string]float64{"cases": 81}}
+	twoDaysAgo := data{date: time.Now().Add(time.Hour * -48), values: map[string]float64{"cases": 400, "deaths": 4}}
+	client := mockRestClient{jsonResponse("{\"data\":[" + asJson(oneDayAgo) + "," + asJson(twoDaysAgo) + "]}")}
+	var out bytes.Buffer
+	log := logging.New(&out, logging.Info)
+	api := NewCovidDataRestApi("http://www.amireallyreal.com", England, client, log)
+
+	if _, err := api.getData(context.Background(), 3, []Metric{Cases, Deaths}); err != nil {
+		t.Fatal(err)
+	}
+	if out.Len() != 0 {
+		t.Fatalf("Expected the warning to wait for Flush, got %s", out.String())
+	}
+	log.Flush()
+
+	expected := "Warning: the api has no new deaths figures for England on these days: " +
+		oneDayAgo.date.Format("02/01") + "\n"
+	if out.String() != expected {
+		t.Fatalf("Expected\n%s\ngot\n%s", expected, out.String())
+	}
+}
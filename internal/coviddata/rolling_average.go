@@ -0,0 +1,78 @@
+package coviddata
+
+import (
+	"strconv"
+	"time"
+)
+
+// rollingAverage replaces each day's value with the mean of the values on that day and the window-1
+// calendar days before it, which smooths out the dips from weekend reporting. A day without a figure,
+// because the api skipped it or the gap policy dropped it or marked it as a gap, isn't in days at all,
+// so the mean is over the days in the window that have one. Estimated figures count like published
+// ones. days and values must be sorted oldest -> newest
+func rollingAverage(days []time.Time, values []float64, window int) []float64 {
+	averages := make([]float64, len(values))
+
+	total, first := 0.0, 0
+	for i, value := range values {
+		total += value
+		for dayNumber(days[i])-dayNumber(days[first]) >= window {
+			total -= values[first]
+			first++
+		}
+
+		averages[i] = total / float64(i-first+1)
+	}
+
+	return averages
+}
+
+// dayNumber counts the calendar days since 1970, so days can be subtracted whatever their time of day
+func dayNumber(t time.Time) int {
+	return int(time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC).Unix() / (24 * 60 * 60))
+}
+
+// smooth averages the values, one for each day of covidData, over the rolling window when there is
+// one. Gaps aren't counted in the averages and are left as 0
+func smooth(covidData []data, values []float64, window int) []float64 {
+	if window <= 1 {
+		return values
+	}
+
+	var days []time.Time
+	var figures []float64
+	for i, d := range covidData {
+		if !d.gap {
+			days, figures = append(days, d.date), append(figures, values[i])
+		}
+	}
+
+	return withGaps(covidData, rollingAverage(days, figures, window))
+}
+
+// extraDays is how much history to fetch before the charted window so the first day's average
+// covers a full window
+func (q Query) extraDays() int {
+	if q.RollingWindow > 1 {
+		return q.RollingWindow - 1
+	}
+	return 0
+}
+
+// isCharted reports whether a day falls within the charted window rather than the extra history
+// fetched for the rolling average
+func (q Query) isCharted(d data) bool {
+	if q.extraDays() == 0 {
+		return true
+	}
+
+	from := time.Now().Add(time.Duration(-q.PreviousDays*24) * time.Hour)
+	return isOnOrAfter(from, d.date)
+}
+
+func (q Query) smoothedTitle(title string) string {
+	if q.RollingWindow > 1 {
+		return title + " (" + strconv.Itoa(q.RollingWindow) + "-day average)"
+	}
+	return title
+}
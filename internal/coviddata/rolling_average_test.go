@@ -0,0 +1,107 @@
+package coviddata
+
+import (
+	"context"
+	"covid-stats-cli/internal/logging"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRollingAverage_AveragesOverTheWindow(t *testing.T) {
+	day := time.Date(2020, 10, 1, 0, 0, 0, 0, time.UTC)
+	days := []time.Time{day, day.AddDate(0, 0, 1), day.AddDate(0, 0, 2), day.AddDate(0, 0, 3), day.AddDate(0, 0, 4)}
+	values := []float64{3, 6, 9, 0, 12}
+
+	averages := rollingAverage(days, values, 3)
+
+	// the first days average over however many days there are so far
+	expected := []float64{3, 4.5, 6, 5, 7}
+	if !reflect.DeepEqual(averages, expected) {
+		t.Fatalf("Expected averages %v but got %v", expected, averages)
+	}
+}
+
+func TestRollingAverage_MissingDaysAreNotCounted(t *testing.T) {
+	day := time.Date(2020, 10, 1, 0, 0, 0, 0, time.UTC)
+	// the 3rd is missing
+	days := []time.Time{day, day.AddDate(0, 0, 1), day.AddDate(0, 0, 3), day.AddDate(0, 0, 4)}
+	values := []float64{3, 6, 9, 12}
+
+	averages := rollingAverage(days, values, 3)
+
+	// the 4th is the average of the 2nd and 4th, not of the 1st, 2nd and 4th
+	expected := []float64{3, 4.5, 7.5, 10.5}
+	if !reflect.DeepEqual(averages, expected) {
+		t.Fatalf("Expected averages %v but got %v", expected, averages)
+	}
+}
+
+func TestHandler_GetSeries_RollingAverageOverCalendarDaysWithAGap(t *testing.T) {
+	today := time.Now()
+	var caseData []data
+	for day := 1; day <= 6; day++ {
+		// 3 days ago is missing
+		if day != 3 {
+			caseData = append(caseData, data{date: today.AddDate(0, 0, -day), values: map[string]float64{"cases": float64(day)}})
+		}
+	}
+	mockApi := givenApiThatReturns(caseData, nil)
+
+	for _, policy := range []GapPolicy{GapMark, GapDrop} {
+		handler := NewHandler(mockApi, logging.Discard())
+		handler.SetGapPolicy(policy)
+
+		result, err := handler.Run(context.Background(), Query{Metric: Cases, PreviousDays: 3, RollingWindow: 3,
+			Render: []Renderer{RenderSeries}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		series := result.Series[0]
+
+		// 2 days ago averages the figures from 4 and 2 days ago. The missing day isn't counted, and 5 days
+		// ago doesn't stand in for it
+		last := series.Rows[len(series.Rows)-1]
+		beforeLast := series.Rows[len(series.Rows)-2]
+		if beforeLast.RollingAverage != 3 || last.RollingAverage != 1.5 {
+			t.Errorf("%s: expected averages of 3 and 1.5 for the last 2 days, got %v", policy, series.Rows)
+		}
+	}
+}
+
+func TestHandler_GetCasesChart_RollingAverageFetchesExtraHistory(t *testing.T) {
+	today := time.Now()
+	var caseData []data
+	for day := 1; day <= 9; day++ {
+		caseData = append(caseData, data{date: today.Add(time.Duration(-24*day) * time.Hour), values: map[string]float64{"cases": float64(day)}})
+	}
+
+	requestedDays := 0
+	mockApi := mockRestApi{mockGetData: func(_ Area, previousDays int) ([]data, error) {
+		requestedDays = previousDays
+		return caseData, nil
+	}}
+	handler := NewHandler(mockApi, logging.Discard())
+
+	result, err := handler.Run(context.Background(), Query{Metric: Cases, PreviousDays: 7, RollingWindow: 3,
+		Render: []Renderer{RenderChart}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	chart := result.Chart
+
+	if requestedDays != 9 {
+		t.Fatalf("Expected 9 days to be fetched for a 3 day average over 7 days, but %d were", requestedDays)
+	}
+
+	if !strings.HasPrefix(chart, "\n----- New cases (3-day average) -----\n") {
+		t.Fatalf("Expected the title to mention the rolling average, got '%s'", chart)
+	}
+
+	// 7 days ago is the oldest day charted, and is the average of 9, 8 and 7 days ago
+	oldest := today.Add(time.Hour*-168).Format("02/01") + " (8) | ********"
+	if !strings.Contains(chart, oldest) || strings.Count(chart, "|") != 7 {
+		t.Fatalf("Expected 7 averaged bars starting with '%s', got '%s'", oldest, chart)
+	}
+}
@@ -0,0 +1,122 @@
+package coviddata
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Series is the figures behind a chart, for exporting. The derived figures are only filled in when
+// the query asks for them
+type Series struct {
+	Metric    Metric
+	Area      Area
+	DateBasis DateBasis
+	// RollingWindow is how many days the rolling averages are over, 0 when there aren't any
+	RollingWindow int
+	Per100k       bool
+	// Rows are sorted oldest -> newest
+	Rows []Row
+}
+
+// Row is a day of a Series
+type Row struct {
+	Date     time.Time
+	AreaCode string
+	// Value is the figure as published, before any rate or average is taken
+	Value                 float64
+	Per100k               float64
+	RollingAverage        float64
+	RollingAveragePer100k float64
+	// Incomplete days are still being reported and their figures are likely to rise
+	Incomplete bool
+	// Estimated days were missing from the api, their figures are filled in by the gap policy
+	Estimated bool
+}
+
+// comparisonSeries turns the fetched results into a series for each of the areas
+func (h Handler) comparisonSeries(q Query, results [][]data) ([]Series, error) {
+	var series []Series
+	for i, covidData := range results {
+		s, err := h.toSeries(q, q.Compare[i], covidData)
+		if err != nil {
+			return nil, err
+		}
+		series = append(series, s)
+	}
+
+	return series, nil
+}
+
+// fetchAreas gets the data for every area at once rather than waiting on each request in turn
+func (h Handler) fetchAreas(ctx context.Context, metric Metric, previousDays int, areas []Area) ([][]data, error) {
+	results := make([][]data, len(areas))
+	errs := make([]error, len(areas))
+	var wg sync.WaitGroup
+	for i, area := range areas {
+		wg.Add(1)
+		go func(i int, area Area) {
+			defer wg.Done()
+			results[i], errs[i] = h.fetch(ctx, h.api.forArea(area), metric, previousDays)
+		}(i, area)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("couldn't fetch the data for %s: %w", areas[i], err)
+		}
+	}
+
+	return results, nil
+}
+
+// toSeries turns the area's data into the series of rows for the charted days
+func (h Handler) toSeries(q Query, area Area, covidData []data) (Series, error) {
+	covidData, _ = h.fillGaps(covidData, q)
+
+	values, rates := make([]float64, len(covidData)), make([]float64, len(covidData))
+	for i, d := range covidData {
+		if d.gap {
+			continue
+		}
+		values[i], _ = d.value(q.Metric)
+		if q.Per100k {
+			var err error
+			rates[i], err = h.populations.per100k(values[i], d.areaCode)
+			if err != nil {
+				return Series{}, err
+			}
+		}
+	}
+
+	averages, averageRates := smooth(covidData, values, q.RollingWindow), smooth(covidData, rates, q.RollingWindow)
+
+	series := Series{Metric: q.Metric, Area: area, DateBasis: q.DateBasis, Per100k: q.Per100k}
+	if q.RollingWindow > 1 {
+		series.RollingWindow = q.RollingWindow
+	}
+
+	now := time.Now()
+	for i, d := range covidData {
+		if d.gap || !q.isCharted(d) {
+			continue
+		}
+
+		row := Row{Date: d.date, AreaCode: d.areaCode, Value: values[i], Incomplete: q.Metric.isIncomplete(d, now),
+			Estimated: d.estimated}
+		if series.Per100k {
+			row.Per100k = rates[i]
+		}
+		if series.RollingWindow > 0 {
+			row.RollingAverage = averages[i]
+			if series.Per100k {
+				row.RollingAveragePer100k = averageRates[i]
+			}
+		}
+		series.Rows = append(series.Rows, row)
+	}
+
+	return series, nil
+}
@@ -0,0 +1,58 @@
+package coviddata
+
+import (
+	"context"
+	"covid-stats-cli/internal/logging"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestHandler_Run_Series_HasTheRawAndDerivedFigures(t *testing.T) {
+	today := time.Now()
+	var caseData []data
+	for day := 1; day <= 3; day++ {
+		caseData = append(caseData, data{date: today.Add(time.Duration(-24*day) * time.Hour), areaCode: "E06000001",
+			values: map[string]float64{"cases": float64(day * 10)}})
+	}
+	handler := NewHandler(givenApiThatReturns(caseData, nil), logging.Discard())
+	handler.SetPopulations(Populations{"E06000001": 50000})
+
+	result, err := handler.Run(context.Background(), Query{Metric: Cases, PreviousDays: 2, RollingWindow: 2, Per100k: true,
+		Render: []Renderer{RenderSeries}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	series := result.Series[0]
+
+	if !series.Per100k || series.RollingWindow != 2 || series.Metric.Name != "cases" {
+		t.Fatalf("Expected the series to say it's per 100k with a 2 day average, got %+v", series)
+	}
+
+	// 3 days ago is only fetched for the average, then 2 days ago has 20 cases and yesterday 10
+	if len(series.Rows) != 2 {
+		t.Fatalf("Expected the 2 charted days, got %+v", series.Rows)
+	}
+
+	newest := series.Rows[1]
+	if newest.Value != 10 || newest.Per100k != 20 || newest.RollingAverage != 15 || math.Abs(newest.RollingAveragePer100k-30) > 1e-9 {
+		t.Fatalf("Expected yesterday's figures to be 10, 20 per 100k, averaging 15 and 30 per 100k, got %+v", newest)
+	}
+}
+
+func TestHandler_Run_ComparisonSeries_HasASeriesPerArea(t *testing.T) {
+	london := Area{Region, "London"}
+	leeds := Area{Ltla, "Leeds"}
+	mockApi := mockRestApi{mockGetData: func(area Area, _ int) ([]data, error) {
+		return []data{{date: time.Now().Add(time.Hour * -24), values: map[string]float64{"deaths": 1}}}, nil
+	}}
+	handler := NewHandler(mockApi, logging.Discard())
+
+	result, err := handler.Run(context.Background(), Query{Metric: Deaths, PreviousDays: 1, Compare: []Area{london, leeds},
+		Render: []Renderer{RenderSeries}})
+	series := result.Series
+
+	if err != nil || len(series) != 2 || series[0].Area != london || series[1].Area != leeds {
+		t.Fatalf("Expected a series for London then Leeds, got %+v and err %v", series, err)
+	}
+}
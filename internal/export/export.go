@@ -0,0 +1,182 @@
+// Package export writes the figures behind the charts in formats other programs can read.
+//
+// The field names and formats are part of the tool's interface, so they shouldn't change once
+// released. Fields can be added, but not renamed or removed:
+//
+//	date                      the day, formatted YYYY-MM-DD
+//	area_type, area_name      the area, e.g. region and London
+//	area_code                 the ONS code of the area, e.g. E12000007
+//	metric                    the metric's name, e.g. cases
+//	value                     the figure as published
+//	per_100k                  the figure per 100,000 people, only with --per-100k
+//	rolling_average           the rolling average of the figure, only with --rolling
+//	rolling_average_per_100k  the rolling average per 100,000 people, only with both
+//	incomplete                true when the day's still being reported
+//	estimated                 true when the api was missing the day, and value is filled in by --gaps
+package export
+
+import (
+	"covid-stats-cli/internal/coviddata"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+type Format string
+
+const (
+	// Chart is the usual chart, rather than an export
+	Chart Format = "chart"
+	JSON  Format = "json"
+	CSV   Format = "csv"
+	TSV   Format = "tsv"
+)
+
+// DateFormat is how the dates are written in every format
+const DateFormat = "2006-01-02"
+
+func ParseFormat(format string) (Format, error) {
+	switch f := Format(strings.ToLower(strings.TrimSpace(format))); f {
+	case Chart, JSON, CSV, TSV:
+		return f, nil
+	}
+
+	return "", fmt.Errorf("'%s' isn't an output format, choose one of: chart, json, csv, tsv", format)
+}
+
+// Write writes the series to w in the given format. Comparisons have a series per area, which are
+// written one after the other
+func Write(w io.Writer, format Format, series []coviddata.Series) error {
+	switch format {
+	case JSON:
+		return writeJSON(w, series)
+	case CSV:
+		return writeDelimited(w, ',', series)
+	case TSV:
+		return writeDelimited(w, '\t', series)
+	}
+
+	return fmt.Errorf("can't export the figures as %s", format)
+}
+
+type jsonSeries struct {
+	Metric        string    `json:"metric"`
+	Title         string    `json:"title"`
+	AreaType      string    `json:"area_type"`
+	AreaName      string    `json:"area_name"`
+	DateBasis     string    `json:"date_basis"`
+	RollingWindow int       `json:"rolling_window,omitempty"`
+	Per100k       bool      `json:"per_100k"`
+	Data          []jsonRow `json:"data"`
+}
+
+type jsonRow struct {
+	Date                  string   `json:"date"`
+	AreaCode              string   `json:"area_code"`
+	Value                 float64  `json:"value"`
+	Per100k               *float64 `json:"per_100k,omitempty"`
+	RollingAverage        *float64 `json:"rolling_average,omitempty"`
+	RollingAveragePer100k *float64 `json:"rolling_average_per_100k,omitempty"`
+	Incomplete            bool     `json:"incomplete"`
+	Estimated             bool     `json:"estimated"`
+}
+
+// writeJSON writes an array with an object per series, so a single area and a comparison have the
+// same shape
+func writeJSON(w io.Writer, series []coviddata.Series) error {
+	out := make([]jsonSeries, 0, len(series))
+	for _, s := range series {
+		js := jsonSeries{
+			Metric:        s.Metric.Name,
+			Title:         s.Metric.Title,
+			AreaType:      string(s.Area.Type),
+			AreaName:      s.Area.Name,
+			DateBasis:     string(s.DateBasis),
+			RollingWindow: s.RollingWindow,
+			Per100k:       s.Per100k,
+			Data:          make([]jsonRow, 0, len(s.Rows)),
+		}
+
+		for _, row := range s.Rows {
+			// copy the row so each pointer is to its own figures
+			row := row
+			jr := jsonRow{Date: row.Date.Format(DateFormat), AreaCode: row.AreaCode, Value: row.Value, Incomplete: row.Incomplete,
+				Estimated: row.Estimated}
+			if s.Per100k {
+				jr.Per100k = &row.Per100k
+			}
+			if s.RollingWindow > 0 {
+				jr.RollingAverage = &row.RollingAverage
+				if s.Per100k {
+					jr.RollingAveragePer100k = &row.RollingAveragePer100k
+				}
+			}
+			js.Data = append(js.Data, jr)
+		}
+
+		out = append(out, js)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(out)
+}
+
+// writeDelimited writes a header then a line per day. The optional columns are included when the
+// first series has them, as every series in a comparison is made with the same settings
+func writeDelimited(w io.Writer, delimiter rune, series []coviddata.Series) error {
+	var per100k, rolling bool
+	if len(series) > 0 {
+		per100k, rolling = series[0].Per100k, series[0].RollingWindow > 0
+	}
+
+	header := []string{"date", "area_type", "area_name", "area_code", "metric", "value"}
+	if per100k {
+		header = append(header, "per_100k")
+	}
+	if rolling {
+		header = append(header, "rolling_average")
+		if per100k {
+			header = append(header, "rolling_average_per_100k")
+		}
+	}
+	header = append(header, "incomplete", "estimated")
+
+	writer := csv.NewWriter(w)
+	writer.Comma = delimiter
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for _, s := range series {
+		for _, row := range s.Rows {
+			record := []string{row.Date.Format(DateFormat), string(s.Area.Type), s.Area.Name, row.AreaCode,
+				s.Metric.Name, formatFloat(row.Value)}
+			if per100k {
+				record = append(record, formatFloat(row.Per100k))
+			}
+			if rolling {
+				record = append(record, formatFloat(row.RollingAverage))
+				if per100k {
+					record = append(record, formatFloat(row.RollingAveragePer100k))
+				}
+			}
+			record = append(record, strconv.FormatBool(row.Incomplete), strconv.FormatBool(row.Estimated))
+
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// formatFloat writes the shortest number that reads back as the same float, e.g. 12 or 12.5
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
@@ -0,0 +1,131 @@
+package export
+
+import (
+	"bytes"
+	"covid-stats-cli/internal/coviddata"
+	"testing"
+	"time"
+)
+
+func givenSeries() []coviddata.Series {
+	london := coviddata.Area{Type: coviddata.Region, Name: "London"}
+	return []coviddata.Series{{
+		Metric:    coviddata.Cases,
+		Area:      london,
+		DateBasis: coviddata.PublishDate,
+		Rows: []coviddata.Row{
+			{Date: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), AreaCode: "E12000007", Value: 100, Estimated: true},
+			{Date: time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC), AreaCode: "E12000007", Value: 150, Incomplete: true},
+		},
+	}}
+}
+
+func givenDerivedSeries() []coviddata.Series {
+	series := givenSeries()
+	series[0].Per100k = true
+	series[0].RollingWindow = 7
+	series[0].Rows[0].Per100k = 1.25
+	series[0].Rows[0].RollingAverage = 90.5
+	series[0].Rows[0].RollingAveragePer100k = 1.125
+	return series
+}
+
+func TestParseFormat(t *testing.T) {
+	format, err := ParseFormat("CSV")
+
+	if err != nil || format != CSV {
+		t.Fatalf("Expected %s but got %s and err %v", CSV, format, err)
+	}
+
+	if _, err := ParseFormat("xml"); err == nil {
+		t.Fatalf("ParseFormat() should return an error for a format it doesn't know")
+	}
+}
+
+func TestWrite_CSV(t *testing.T) {
+	var out bytes.Buffer
+
+	err := Write(&out, CSV, givenSeries())
+
+	expected := "date,area_type,area_name,area_code,metric,value,incomplete,estimated\n" +
+		"2021-01-01,region,London,E12000007,cases,100,false,true\n" +
+		"2021-01-02,region,London,E12000007,cases,150,true,false\n"
+	if err != nil || out.String() != expected {
+		t.Fatalf("Expected:\n%s\nGot:\n%s\nand err %v", expected, out.String(), err)
+	}
+}
+
+func TestWrite_TSVWithDerivedColumns(t *testing.T) {
+	var out bytes.Buffer
+
+	err := Write(&out, TSV, givenDerivedSeries())
+
+	expected := "date\tarea_type\tarea_name\tarea_code\tmetric\tvalue\tper_100k\trolling_average\trolling_average_per_100k\tincomplete\testimated\n" +
+		"2021-01-01\tregion\tLondon\tE12000007\tcases\t100\t1.25\t90.5\t1.125\tfalse\ttrue\n" +
+		"2021-01-02\tregion\tLondon\tE12000007\tcases\t150\t0\t0\t0\ttrue\tfalse\n"
+	if err != nil || out.String() != expected {
+		t.Fatalf("Expected:\n%s\nGot:\n%s\nand err %v", expected, out.String(), err)
+	}
+}
+
+func TestWrite_JSON(t *testing.T) {
+	var out bytes.Buffer
+
+	err := Write(&out, JSON, givenDerivedSeries())
+
+	expected := `[
+  {
+    "metric": "cases",
+    "title": "New cases",
+    "area_type": "region",
+    "area_name": "London",
+    "date_basis": "publish",
+    "rolling_window": 7,
+    "per_100k": true,
+    "data": [
+      {
+        "date": "2021-01-01",
+        "area_code": "E12000007",
+        "value": 100,
+        "per_100k": 1.25,
+        "rolling_average": 90.5,
+        "rolling_average_per_100k": 1.125,
+        "incomplete": false,
+        "estimated": true
+      },
+      {
+        "date": "2021-01-02",
+        "area_code": "E12000007",
+        "value": 150,
+        "per_100k": 0,
+        "rolling_average": 0,
+        "rolling_average_per_100k": 0,
+        "incomplete": true,
+        "estimated": false
+      }
+    ]
+  }
+]
+`
+	if err != nil || out.String() != expected {
+		t.Fatalf("Expected:\n%s\nGot:\n%s\nand err %v", expected, out.String(), err)
+	}
+}
+
+func TestWrite_JSONLeavesOutTheDerivedFieldsWhenOff(t *testing.T) {
+	var out bytes.Buffer
+
+	if err := Write(&out, JSON, givenSeries()); err != nil {
+		t.Fatal(err)
+	}
+
+	if bytes.Contains(out.Bytes(), []byte("rolling_average")) || bytes.Contains(out.Bytes(), []byte(`"per_100k": 1`)) {
+		t.Fatalf("Expected no derived figures, got %s", out.String())
+	}
+}
+
+func TestWrite_Chart(t *testing.T) {
+	if err := Write(&bytes.Buffer{}, Chart, givenSeries()); err == nil {
+		t.Fatalf("Write() should return an error for the chart format, which isn't an export")
+	}
+}
@@ -0,0 +1,111 @@
+// Package logging writes what the cli's doing to stderr, at the level the user asks for, and
+// gathers up warnings that would otherwise repeat, e.g. once for each day with a missing figure
+package logging
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"sync"
+)
+
+type Level int
+
+const (
+	// Debug is for the detail that's only wanted when something's wrong, e.g. each request
+	Debug Level = iota
+	// Info is for what's useful to know, e.g. where a chart was saved
+	Info
+	// Warn is for problems with the data that don't stop it being charted
+	Warn
+	// Error is for what stops the cli doing what it was asked
+	Error
+)
+
+// the most details a collected warning lists before saying how many more there are
+const maxDetails = 5
+
+// Logger writes the messages at or above its level. It's safe to use from several goroutines, e.g.
+// when fetching areas at the same time
+type Logger struct {
+	mu        sync.Mutex
+	w         io.Writer
+	level     Level
+	order     []string
+	collected map[string][]string
+}
+
+// New logs to w, usually stderr, so the messages don't get mixed into charts or figures on stdout
+func New(w io.Writer, level Level) *Logger {
+	return &Logger{w: w, level: level, collected: make(map[string][]string)}
+}
+
+// Discard logs nothing, e.g. for tests
+func Discard() *Logger {
+	return New(ioutil.Discard, Error+1)
+}
+
+func (l *Logger) Debugf(format string, args ...interface{}) {
+	l.logf(Debug, "", format, args...)
+}
+
+func (l *Logger) Infof(format string, args ...interface{}) {
+	l.logf(Info, "", format, args...)
+}
+
+func (l *Logger) Warnf(format string, args ...interface{}) {
+	l.logf(Warn, "Warning: ", format, args...)
+}
+
+func (l *Logger) Errorf(format string, args ...interface{}) {
+	l.logf(Error, "Error: ", format, args...)
+}
+
+func (l *Logger) logf(level Level, prefix string, format string, args ...interface{}) {
+	if level < l.level {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	fmt.Fprintf(l.w, prefix+format+"\n", args...)
+}
+
+// Collect keeps a warning to write once with Flush, listing each detail, e.g. the days a figure is
+// missing on. A detail that's already been collected for the warning isn't repeated
+func (l *Logger) Collect(warning string, detail string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	details, ok := l.collected[warning]
+	if !ok {
+		l.order = append(l.order, warning)
+	}
+	for _, d := range details {
+		if d == detail {
+			return
+		}
+	}
+	l.collected[warning] = append(details, detail)
+}
+
+// Flush writes each collected warning, in the order they were first collected, and forgets them
+func (l *Logger) Flush() {
+	l.mu.Lock()
+	order, collected := l.order, l.collected
+	l.order, l.collected = nil, make(map[string][]string)
+	l.mu.Unlock()
+
+	for _, warning := range order {
+		l.Warnf("%s: %s", warning, summarise(collected[warning]))
+	}
+}
+
+// summarise lists the details, or the first few and how many more there are
+func summarise(details []string) string {
+	if len(details) <= maxDetails {
+		return strings.Join(details, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(details[:maxDetails], ", "), len(details)-maxDetails)
+}
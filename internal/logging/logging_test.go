@@ -0,0 +1,66 @@
+package logging
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestLogger_OnlyWritesAtOrAboveItsLevel(t *testing.T) {
+	var out bytes.Buffer
+	log := New(&out, Warn)
+
+	log.Debugf("fetching %s", "cases")
+	log.Infof("saved the chart")
+	log.Warnf("the figures look odd")
+	log.Errorf("it broke")
+
+	expected := "Warning: the figures look odd\nError: it broke\n"
+	if out.String() != expected {
+		t.Fatalf("Expected\n%s\ngot\n%s", expected, out.String())
+	}
+}
+
+func TestLogger_FlushWritesEachCollectedWarningOnce(t *testing.T) {
+	var out bytes.Buffer
+	log := New(&out, Info)
+
+	log.Collect("no cases figure for England on", "01/02")
+	log.Collect("no deaths figure for England on", "01/02")
+	log.Collect("no cases figure for England on", "03/02")
+	log.Collect("no cases figure for England on", "01/02")
+	log.Flush()
+	log.Flush()
+
+	expected := "Warning: no cases figure for England on: 01/02, 03/02\n" +
+		"Warning: no deaths figure for England on: 01/02\n"
+	if out.String() != expected {
+		t.Fatalf("Expected\n%s\ngot\n%s", expected, out.String())
+	}
+}
+
+func TestLogger_FlushShortensLongLists(t *testing.T) {
+	var out bytes.Buffer
+	log := New(&out, Info)
+
+	for _, day := range []string{"01/02", "02/02", "03/02", "04/02", "05/02", "06/02", "07/02"} {
+		log.Collect("no cases figure on", day)
+	}
+	log.Flush()
+
+	expected := "Warning: no cases figure on: 01/02, 02/02, 03/02, 04/02, 05/02 and 2 more\n"
+	if out.String() != expected {
+		t.Fatalf("Expected\n%s\ngot\n%s", expected, out.String())
+	}
+}
+
+func TestLogger_QuietHidesCollectedWarnings(t *testing.T) {
+	var out bytes.Buffer
+	log := New(&out, Error)
+
+	log.Collect("no cases figure on", "01/02")
+	log.Flush()
+
+	if out.Len() != 0 {
+		t.Fatalf("Expected nothing, got %s", out.String())
+	}
+}
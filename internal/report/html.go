@@ -0,0 +1,44 @@
+package report
+
+import (
+	"html"
+	"strings"
+)
+
+// html is a standalone page with the charts drawn inline as SVGs
+func (r Report) html() string {
+	var page strings.Builder
+
+	page.WriteString("<!DOCTYPE html>\n")
+	page.WriteString("<html lang=\"en\">\n")
+	page.WriteString("<head>\n")
+	page.WriteString("<meta charset=\"utf-8\">\n")
+	page.WriteString("<title>" + html.EscapeString(r.Title) + "</title>\n")
+	page.WriteString("<style>body { font-family: sans-serif; max-width: 820px; margin: auto; } " +
+		"table { border-collapse: collapse; } th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; }</style>\n")
+	page.WriteString("</head>\n")
+	page.WriteString("<body>\n")
+	page.WriteString("<h1>" + html.EscapeString(r.Title) + "</h1>\n")
+
+	for _, section := range r.Sections {
+		page.WriteString("<section>\n")
+		page.WriteString("<h2>" + html.EscapeString(section.Summary.Title) + "</h2>\n")
+		page.WriteString(section.SVG)
+
+		page.WriteString("<table>\n")
+		page.WriteString("<tr><th>Figure</th><th>Value</th></tr>\n")
+		for _, field := range section.Summary.Fields() {
+			page.WriteString("<tr><td>" + html.EscapeString(field.Name) + "</td><td>" + html.EscapeString(field.Value) + "</td></tr>\n")
+		}
+		page.WriteString("</table>\n")
+		page.WriteString("</section>\n")
+	}
+
+	page.WriteString("<footer>\n")
+	page.WriteString("<p>" + html.EscapeString(r.footer()) + "</p>\n")
+	page.WriteString("</footer>\n")
+	page.WriteString("</body>\n")
+	page.WriteString("</html>\n")
+
+	return page.String()
+}
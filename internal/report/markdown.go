@@ -0,0 +1,35 @@
+package report
+
+import "strings"
+
+// markdown has a heading per section, with the text chart in a code block and the summary in a table
+func (r Report) markdown() string {
+	var md strings.Builder
+
+	md.WriteString("# " + r.Title + "\n\n")
+
+	for _, section := range r.Sections {
+		md.WriteString("## " + section.Summary.Title + "\n\n")
+
+		md.WriteString("```text\n")
+		md.WriteString(strings.Trim(section.Chart, "\n") + "\n")
+		md.WriteString("```\n\n")
+
+		md.WriteString("| Figure | Value |\n")
+		md.WriteString("| --- | --- |\n")
+		for _, field := range section.Summary.Fields() {
+			md.WriteString("| " + escapeTableCell(field.Name) + " | " + escapeTableCell(field.Value) + " |\n")
+		}
+		md.WriteString("\n")
+	}
+
+	md.WriteString("---\n\n")
+	md.WriteString(r.footer() + "\n")
+
+	return md.String()
+}
+
+// escapeTableCell stops a | in the text from starting a new column
+func escapeTableCell(text string) string {
+	return strings.ReplaceAll(text, "|", "\\|")
+}
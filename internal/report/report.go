@@ -0,0 +1,132 @@
+// Package report builds documents, e.g. for a weekly status update, with a chart and a summary for
+// each metric and area
+package report
+
+import (
+	"bytes"
+	"context"
+	"covid-stats-cli/internal/coviddata"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+type Format string
+
+const (
+	Markdown Format = "markdown"
+	HTML     Format = "html"
+)
+
+func ParseFormat(format string) (Format, error) {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "markdown", "md":
+		return Markdown, nil
+	case "html":
+		return HTML, nil
+	}
+
+	return "", fmt.Errorf("'%s' isn't a report format, choose one of: markdown, html", format)
+}
+
+// Source runs the queries for the figures, which a *coviddata.Handler does
+type Source interface {
+	Run(ctx context.Context, query coviddata.Query) (coviddata.Result, error)
+	LastUpdated() time.Time
+}
+
+type Report struct {
+	Title     string
+	Generated time.Time
+	// LastUpdated is when the api last updated the figures, zero when it didn't say
+	LastUpdated time.Time
+	// SourceUrl is where the figures came from
+	SourceUrl string
+	Sections  []Section
+}
+
+// Section is a metric in an area
+type Section struct {
+	Metric coviddata.Metric
+	Area   coviddata.Area
+	// Chart is the text chart and SVG the same chart as an image
+	Chart   string
+	SVG     string
+	Summary coviddata.Summary
+}
+
+// Build runs query for every metric in every area, area by area, for a section each. query says
+// the days and how the figures are aggregated and transformed, Build fills in the rest. It stops as
+// soon as ctx is cancelled
+func Build(ctx context.Context, metrics []coviddata.Metric, areas []coviddata.Area, query coviddata.Query, source Source,
+	sourceUrl string, now time.Time) (Report, error) {
+	r := Report{
+		Title:     fmt.Sprintf("COVID-19 report for the last %d days", query.PreviousDays),
+		Generated: now,
+		SourceUrl: sourceUrl,
+	}
+
+	for _, area := range areas {
+		for _, metric := range metrics {
+			query.Metric, query.Area = metric, area
+			section, err := buildSection(ctx, source, query)
+			if err != nil {
+				return Report{}, fmt.Errorf("couldn't report the %s for %s: %w", metric.Name, area, err)
+			}
+			r.Sections = append(r.Sections, section)
+		}
+
+		if updated := source.LastUpdated(); updated.After(r.LastUpdated) {
+			r.LastUpdated = updated
+		}
+	}
+
+	return r, nil
+}
+
+// buildSection renders the query as a chart, an image and a summary, all from the one fetch
+func buildSection(ctx context.Context, source Source, query coviddata.Query) (Section, error) {
+	query.Render = []coviddata.Renderer{coviddata.RenderChart, coviddata.RenderBarChart, coviddata.RenderSummary}
+	result, err := source.Run(ctx, query)
+	if err != nil {
+		return Section{}, err
+	}
+
+	var svg bytes.Buffer
+	if err := result.BarChart.WriteSVG(&svg); err != nil {
+		return Section{}, err
+	}
+
+	return Section{Metric: query.Metric, Area: query.Area, Chart: result.Chart, SVG: svg.String(),
+		Summary: result.Summary}, nil
+}
+
+// Write writes the report to w as a markdown or HTML document
+func (r Report) Write(w io.Writer, format Format) error {
+	var document string
+	switch format {
+	case Markdown:
+		document = r.markdown()
+	case HTML:
+		document = r.html()
+	default:
+		return fmt.Errorf("can't write a report as %s", format)
+	}
+
+	_, err := io.WriteString(w, document)
+	return err
+}
+
+// footer says where the figures came from and how up to date they are
+func (r Report) footer() string {
+	updated := "an unknown time"
+	if !r.LastUpdated.IsZero() {
+		updated = r.LastUpdated.UTC().Format(timestampFormat)
+	}
+
+	return fmt.Sprintf("Data from the UK coronavirus dashboard api, %s, last updated %s. Generated %s.",
+		r.SourceUrl, updated, r.Generated.UTC().Format(timestampFormat))
+}
+
+const timestampFormat = "2 January 2006 15:04 MST"
@@ -0,0 +1,174 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"covid-stats-cli/internal/barchart"
+	"covid-stats-cli/internal/coviddata"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fakeSource struct {
+	err         error
+	lastUpdated time.Time
+	// runs counts the queries run, when it's set
+	runs *int
+}
+
+func (f fakeSource) Run(_ context.Context, query coviddata.Query) (coviddata.Result, error) {
+	if f.runs != nil {
+		*f.runs++
+	}
+
+	title := query.Metric.Title + " in " + query.Area.Name
+	var result coviddata.Result
+	for _, render := range query.Render {
+		switch render {
+		case coviddata.RenderChart:
+			result.Chart = "\n----- " + title + " -----\n\n01/01 (5) | *****  \n\n"
+		case coviddata.RenderBarChart:
+			chart, err := barchart.NewBarChart(title, []barchart.Bar{barchart.NewBar("01/01", 5)})
+			if err != nil {
+				return coviddata.Result{}, err
+			}
+			result.BarChart = chart
+		case coviddata.RenderSummary:
+			result.Summary = coviddata.Summary{Title: title, ThisWeek: 5, LastWeek: 4, HasChange: true, PercentChange: 25}
+		}
+	}
+	return result, f.err
+}
+
+func (f fakeSource) LastUpdated() time.Time {
+	return f.lastUpdated
+}
+
+var (
+	london  = coviddata.Area{Type: coviddata.Region, Name: "London"}
+	updated = time.Date(2021, 1, 11, 15, 10, 0, 0, time.UTC)
+	now     = time.Date(2021, 1, 12, 9, 0, 0, 0, time.UTC)
+	week    = coviddata.Query{PreviousDays: 7}
+)
+
+func givenReport(t *testing.T) Report {
+	r, err := Build(context.Background(), []coviddata.Metric{coviddata.Cases}, []coviddata.Area{london}, week,
+		fakeSource{lastUpdated: updated}, "https://api.example.com/v1/data", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestParseFormat(t *testing.T) {
+	format, err := ParseFormat("md")
+
+	if err != nil || format != Markdown {
+		t.Fatalf("Expected %s but got %s and err %v", Markdown, format, err)
+	}
+
+	if _, err := ParseFormat("pdf"); err == nil {
+		t.Fatalf("ParseFormat() should return an error for a format it doesn't know")
+	}
+}
+
+func TestBuild_HasASectionPerMetricInEachArea(t *testing.T) {
+	leeds := coviddata.Area{Type: coviddata.Ltla, Name: "Leeds"}
+	metrics := []coviddata.Metric{coviddata.Cases, coviddata.Deaths}
+
+	r, err := Build(context.Background(), metrics, []coviddata.Area{london, leeds}, week, fakeSource{}, "", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var titles []string
+	for _, section := range r.Sections {
+		titles = append(titles, section.Summary.Title)
+	}
+	expected := "New cases in London, New deaths in London, New cases in Leeds, New deaths in Leeds"
+	if strings.Join(titles, ", ") != expected {
+		t.Fatalf("Expected sections for %s, got %v", expected, titles)
+	}
+}
+
+func TestBuild_RunsOneQueryPerSection(t *testing.T) {
+	runs := 0
+	metrics := []coviddata.Metric{coviddata.Cases, coviddata.Deaths}
+
+	r, err := Build(context.Background(), metrics, []coviddata.Area{london}, week, fakeSource{runs: &runs}, "", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the chart, image and summary of a section all come from the same fetch
+	if runs != len(r.Sections) {
+		t.Fatalf("Expected %d queries for %d sections, got %d", len(r.Sections), len(r.Sections), runs)
+	}
+}
+
+func TestBuild_SourceFails(t *testing.T) {
+	apiErr := errors.New("our data centre went bye bye")
+
+	_, err := Build(context.Background(), []coviddata.Metric{coviddata.Cases}, []coviddata.Area{london}, week, fakeSource{err: apiErr}, "", now)
+
+	if !errors.Is(err, apiErr) {
+		t.Fatalf("Expected error %v to wrap %v", err, apiErr)
+	}
+}
+
+func TestReport_WriteMarkdown(t *testing.T) {
+	var out bytes.Buffer
+
+	if err := givenReport(t).Write(&out, Markdown); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "# COVID-19 report for the last 7 days\n\n" +
+		"## New cases in London\n\n" +
+		"```text\n" +
+		"----- New cases in London -----\n\n" +
+		"01/01 (5) | *****  \n" +
+		"```\n\n" +
+		"| Figure | Value |\n" +
+		"| --- | --- |\n" +
+		"| This week | 5 |\n" +
+		"| Last week | 4 |\n" +
+		"| Change | +25.0% |\n\n" +
+		"---\n\n" +
+		"Data from the UK coronavirus dashboard api, https://api.example.com/v1/data, last updated " +
+		"11 January 2021 15:10 UTC. Generated 12 January 2021 09:00 UTC.\n"
+	if out.String() != expected {
+		t.Fatalf("Expected:\n%s\nGot:\n%s", expected, out.String())
+	}
+}
+
+func TestReport_WriteHTML(t *testing.T) {
+	var out bytes.Buffer
+
+	if err := givenReport(t).Write(&out, HTML); err != nil {
+		t.Fatal(err)
+	}
+
+	page := out.String()
+	for _, expected := range []string{
+		"<h2>New cases in London</h2>\n<svg xmlns=\"http://www.w3.org/2000/svg\"",
+		"<tr><td>Change</td><td>+25.0%</td></tr>",
+		"last updated 11 January 2021 15:10 UTC",
+		"</html>\n",
+	} {
+		if !strings.Contains(page, expected) {
+			t.Fatalf("Expected the page to contain '%s', got:\n%s", expected, page)
+		}
+	}
+}
+
+func TestReport_FooterWhenTheApiDidntSayWhenItUpdated(t *testing.T) {
+	r := givenReport(t)
+	r.LastUpdated = time.Time{}
+
+	if !strings.Contains(r.footer(), "last updated an unknown time") {
+		t.Fatalf("Expected the footer to say the update time is unknown, got %s", r.footer())
+	}
+}
@@ -0,0 +1,247 @@
+package rest
+
+import (
+	"bytes"
+	"context"
+	"covid-stats-cli/internal/logging"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+)
+
+type CacheMode int
+
+const (
+	// UseCache reuses a cached response until the dashboard publishes its next daily update
+	UseCache CacheMode = iota
+	// RefreshCache always fetches a new response and caches it
+	RefreshCache
+	// Offline only ever uses cached responses, however old they are
+	Offline
+)
+
+// the dashboard publishes its daily update at 4pm UK time
+const publishHour = 16
+
+// dateFilter is the dashboard's filter for the days after a date, e.g. ;date>2021-01-03, which moves
+// on every day so isn't part of the cache key
+var dateFilter = regexp.MustCompile(`;date(?:>|%3[Ee])(\d{4}-\d{2}-\d{2})`)
+
+var ErrNotCached = errors.New("there's no cached copy of the data to use offline")
+
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func SystemClock() Clock {
+	return systemClock{}
+}
+
+type cacheEntry struct {
+	Url       string
+	FetchedAt time.Time
+	// LastModified is the response's Last-Modified header, which says when the api last updated
+	LastModified string `json:",omitempty"`
+	Body         []byte
+}
+
+type cachingClient struct {
+	client Client
+	dir    string
+	clock  Clock
+	mode   CacheMode
+	log    *logging.Logger
+}
+
+// NewCachingClient wraps client so successful responses are stored in dir, keyed by url without its
+// date filter, so there's one file per query rather than one a day. When the
+// api can't be reached or fails, a stale copy is used instead unless mode is RefreshCache, and log
+// collects a warning that the figures may be out of date
+func NewCachingClient(client Client, dir string, clock Clock, mode CacheMode, log *logging.Logger) Client {
+	return cachingClient{client, dir, clock, mode, log}
+}
+
+// DefaultCacheDir is where responses are cached unless told otherwise, e.g. ~/.cache/covid-stats-cli
+func DefaultCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "covid-stats-cli"), nil
+}
+
+// Get makes the request conditional on there being something newer than the cached copy, unless
+// refreshing, and uses the cached copy when the api says there isn't
+func (c cachingClient) Get(ctx context.Context, url string, header http.Header) (resp *http.Response, err error) {
+	entry, cached := c.read(url)
+
+	if c.mode == Offline {
+		if !cached {
+			return nil, ErrNotCached
+		}
+		return entry.response(), nil
+	}
+
+	if c.mode == UseCache && cached && c.isFresh(entry) {
+		return entry.response(), nil
+	}
+
+	resp, err = c.client.Get(ctx, url, c.conditional(header, entry, cached))
+	// a stale copy is better than nothing when the api can't be reached or is failing, but not when
+	// the fetch was cancelled or the user asked for the latest figures
+	if c.mode == UseCache && cached && ctx.Err() == nil && (err != nil || resp.StatusCode >= 500) {
+		if resp != nil {
+			resp.Body.Close()
+		}
+		c.log.Debugf("GET %s: %s, using the copy cached at %s", url, outcome(resp, err), entry.FetchedAt.Format(time.RFC3339))
+		c.log.Collect("couldn't get the latest figures from the api, so these are cached ones that may be out of date",
+			"fetched "+entry.FetchedAt.Local().Format("02/01 15:04"))
+		return entry.response(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotModified && cached && c.mode == UseCache {
+		resp.Body.Close()
+		// the cached copy is the latest, so it's fresh until the next publish
+		entry.FetchedAt = c.clock.Now()
+		_ = c.write(entry)
+		return entry.response(), nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return resp, nil
+	}
+
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	entry = cacheEntry{Url: url, FetchedAt: c.clock.Now(), LastModified: resp.Header.Get("Last-Modified"), Body: body}
+	// failing to cache shouldn't stop the data being shown, it just means fetching it again next time
+	_ = c.write(entry)
+
+	return entry.response(), nil
+}
+
+// conditional adds an If-Modified-Since for the cached copy to header, when there's a cached copy
+// that says when it was last modified and it isn't being refreshed. header itself isn't changed
+func (c cachingClient) conditional(header http.Header, entry cacheEntry, cached bool) http.Header {
+	if !cached || c.mode != UseCache || entry.LastModified == "" {
+		return header
+	}
+
+	conditional := header.Clone()
+	if conditional == nil {
+		conditional = make(http.Header)
+	}
+	conditional.Set("If-Modified-Since", entry.LastModified)
+	return conditional
+}
+
+// isFresh reports whether the entry was fetched after the most recent daily publish
+func (c cachingClient) isFresh(entry cacheEntry) bool {
+	return !entry.FetchedAt.Before(lastPublishTime(c.clock.Now()))
+}
+
+func lastPublishTime(now time.Time) time.Time {
+	location, err := time.LoadLocation("Europe/London")
+	if err != nil {
+		location = time.UTC
+	}
+
+	now = now.In(location)
+	published := time.Date(now.Year(), now.Month(), now.Day(), publishHour, 0, 0, 0, location)
+	if now.Before(published) {
+		published = published.AddDate(0, 0, -1)
+	}
+
+	return published
+}
+
+func (c cachingClient) path(url string) string {
+	hash := sha256.Sum256([]byte(cacheKey(url)))
+	return filepath.Join(c.dir, hex.EncodeToString(hash[:])+".json")
+}
+
+func cacheKey(url string) string {
+	return dateFilter.ReplaceAllString(url, "")
+}
+
+// covers reports whether the response for cachedUrl has every day url asks for, i.e. it's for the
+// same query from the same day or earlier
+func covers(cachedUrl string, url string) bool {
+	if cacheKey(cachedUrl) != cacheKey(url) {
+		return false
+	}
+
+	cachedFrom, from := dateFilter.FindStringSubmatch(cachedUrl), dateFilter.FindStringSubmatch(url)
+	if cachedFrom == nil || from == nil {
+		return cachedUrl == url
+	}
+	// the dates are yyyy-mm-dd so sort as strings
+	return cachedFrom[1] <= from[1]
+}
+
+// read finds the cached copy of the query, which is only used when it goes back as far as url asks
+func (c cachingClient) read(url string) (cacheEntry, bool) {
+	bytes, err := ioutil.ReadFile(c.path(url))
+	if err != nil {
+		return cacheEntry{}, false
+	}
+
+	var entry cacheEntry
+	if err := json.Unmarshal(bytes, &entry); err != nil || !covers(entry.Url, url) {
+		return cacheEntry{}, false
+	}
+
+	return entry, true
+}
+
+func (c cachingClient) write(entry cacheEntry) error {
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return err
+	}
+
+	bytes, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	// write then rename so a half written file is never read back
+	tmp := c.path(entry.Url) + ".tmp"
+	if err := ioutil.WriteFile(tmp, bytes, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, c.path(entry.Url))
+}
+
+func (e cacheEntry) response() *http.Response {
+	header := make(http.Header)
+	if e.LastModified != "" {
+		header.Set("Last-Modified", e.LastModified)
+	}
+
+	return &http.Response{
+		Status:     "200 OK",
+		StatusCode: http.StatusOK,
+		Header:     header,
+		Body:       ioutil.NopCloser(bytes.NewReader(e.Body)),
+	}
+}
@@ -0,0 +1,310 @@
+package rest
+
+import (
+	"bytes"
+	"context"
+	"covid-stats-cli/internal/logging"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+type countingClient struct {
+	calls        int
+	body         string
+	lastModified string
+	err          error
+	status       int
+	// header is what the last request was sent with
+	header http.Header
+}
+
+func (c *countingClient) Get(_ context.Context, _ string, header http.Header) (resp *http.Response, err error) {
+	c.calls++
+	c.header = header
+	if c.err != nil {
+		return nil, c.err
+	}
+	respHeader := make(http.Header)
+	if c.lastModified != "" {
+		respHeader.Set("Last-Modified", c.lastModified)
+	}
+	status := c.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	return &http.Response{
+		StatusCode: status,
+		Header:     respHeader,
+		Body:       ioutil.NopCloser(bytes.NewBufferString(c.body)),
+	}, nil
+}
+
+func givenCacheDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "covid-stats-cli-cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		os.RemoveAll(dir)
+	})
+	return dir
+}
+
+func readBody(t *testing.T, client Client) string {
+	resp, err := client.Get(context.Background(), "http://www.amireallyreal.com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(body)
+}
+
+func TestCachingClient_ReusesResponseUntilNextPublish(t *testing.T) {
+	dir := givenCacheDir(t)
+	clock := &fakeClock{time.Date(2021, 1, 10, 10, 0, 0, 0, time.UTC)}
+	client := &countingClient{body: "first"}
+	cachingClient := NewCachingClient(client, dir, clock, UseCache, logging.Discard())
+
+	readBody(t, cachingClient)
+	client.body = "second"
+
+	// still before the 4pm publish
+	clock.now = time.Date(2021, 1, 10, 15, 59, 0, 0, time.UTC)
+	if body := readBody(t, cachingClient); body != "first" || client.calls != 1 {
+		t.Fatalf("Expected the cached 'first' after 1 call, got '%s' after %d calls", body, client.calls)
+	}
+
+	clock.now = time.Date(2021, 1, 10, 16, 1, 0, 0, time.UTC)
+	if body := readBody(t, cachingClient); body != "second" || client.calls != 2 {
+		t.Fatalf("Expected a fresh 'second' after 2 calls, got '%s' after %d calls", body, client.calls)
+	}
+}
+
+func TestCachingClient_RefreshAlwaysFetches(t *testing.T) {
+	dir := givenCacheDir(t)
+	clock := &fakeClock{time.Date(2021, 1, 10, 10, 0, 0, 0, time.UTC)}
+	client := &countingClient{body: "first"}
+
+	readBody(t, NewCachingClient(client, dir, clock, UseCache, logging.Discard()))
+	client.body = "second"
+
+	if body := readBody(t, NewCachingClient(client, dir, clock, RefreshCache, logging.Discard())); body != "second" || client.calls != 2 {
+		t.Fatalf("Expected a refreshed 'second' after 2 calls, got '%s' after %d calls", body, client.calls)
+	}
+
+	// the refreshed copy replaces the old one in the cache
+	if body := readBody(t, NewCachingClient(client, dir, clock, UseCache, logging.Discard())); body != "second" || client.calls != 2 {
+		t.Fatalf("Expected the cached 'second' after 2 calls, got '%s' after %d calls", body, client.calls)
+	}
+}
+
+func TestCachingClient_OfflineUsesStaleCopy(t *testing.T) {
+	dir := givenCacheDir(t)
+	clock := &fakeClock{time.Date(2021, 1, 10, 10, 0, 0, 0, time.UTC)}
+	client := &countingClient{body: "first"}
+
+	readBody(t, NewCachingClient(client, dir, clock, UseCache, logging.Discard()))
+
+	clock.now = clock.now.AddDate(0, 1, 0)
+	if body := readBody(t, NewCachingClient(client, dir, clock, Offline, logging.Discard())); body != "first" || client.calls != 1 {
+		t.Fatalf("Expected the stale 'first' after 1 call, got '%s' after %d calls", body, client.calls)
+	}
+}
+
+func TestCachingClient_KeysOnTheQueryWithoutItsDateFilter(t *testing.T) {
+	dir := givenCacheDir(t)
+	clock := &fakeClock{time.Date(2021, 1, 10, 10, 0, 0, 0, time.UTC)}
+	client := &countingClient{body: "first"}
+	yesterday := "http://www.amireallyreal.com?filters=areaType=nation;date%3E2021-01-02&structure={}"
+	today := "http://www.amireallyreal.com?filters=areaType=nation;date%3E2021-01-03&structure={}"
+
+	if _, err := NewCachingClient(client, dir, clock, UseCache, logging.Discard()).Get(context.Background(), yesterday, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	clock.now = clock.now.AddDate(0, 0, 1)
+	resp, err := NewCachingClient(client, dir, clock, Offline, logging.Discard()).Get(context.Background(), today, nil)
+	if err != nil {
+		t.Fatalf("Expected yesterday's copy of the query to be used offline today, got %v", err)
+	}
+	resp.Body.Close()
+
+	files, err := ioutil.ReadDir(dir)
+	if err != nil || len(files) != 1 {
+		t.Fatalf("Expected one cached file for the query, got %d and err %v", len(files), err)
+	}
+}
+
+func TestCachingClient_DoesNotUseACopyThatDoesNotGoBackFarEnough(t *testing.T) {
+	dir := givenCacheDir(t)
+	clock := &fakeClock{time.Date(2021, 1, 10, 10, 0, 0, 0, time.UTC)}
+	client := &countingClient{body: "a week"}
+	week := "http://www.amireallyreal.com?filters=areaType=nation;date%3E2021-01-02&structure={}"
+	month := "http://www.amireallyreal.com?filters=areaType=nation;date%3E2020-12-09&structure={}"
+
+	if _, err := NewCachingClient(client, dir, clock, UseCache, logging.Discard()).Get(context.Background(), week, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := NewCachingClient(client, dir, clock, Offline, logging.Discard()).Get(context.Background(), month, nil)
+	if err != ErrNotCached {
+		t.Fatalf("Expected a week's figures not to be used for a month's, got %v", err)
+	}
+}
+
+func TestCachingClient_KeepsLastModified(t *testing.T) {
+	dir := givenCacheDir(t)
+	client := &countingClient{body: "first", lastModified: "Sun, 10 Jan 2021 15:02:11 GMT"}
+	clock := &fakeClock{time.Date(2021, 1, 10, 16, 30, 0, 0, time.UTC)}
+
+	readBody(t, NewCachingClient(client, dir, clock, UseCache, logging.Discard()))
+	resp, err := NewCachingClient(client, dir, clock, Offline, logging.Discard()).Get(context.Background(), "http://www.amireallyreal.com", nil)
+
+	if err != nil || resp.Header.Get("Last-Modified") != client.lastModified {
+		t.Fatalf("Expected the cached response to be last modified %s, got %v and err %v",
+			client.lastModified, resp, err)
+	}
+}
+
+func TestCachingClient_UsesCachedCopyWhenNotModified(t *testing.T) {
+	dir := givenCacheDir(t)
+	clock := &fakeClock{time.Date(2021, 1, 10, 10, 0, 0, 0, time.UTC)}
+	client := &countingClient{body: "first", lastModified: "Sat, 09 Jan 2021 15:02:11 GMT"}
+	cachingClient := NewCachingClient(client, dir, clock, UseCache, logging.Discard())
+
+	readBody(t, cachingClient)
+	client.body, client.status = "", http.StatusNotModified
+	clock.now = clock.now.AddDate(0, 0, 1)
+
+	if body := readBody(t, cachingClient); body != "first" || client.calls != 2 {
+		t.Fatalf("Expected the cached 'first' after 2 calls, got '%s' after %d calls", body, client.calls)
+	}
+	if since := client.header.Get("If-Modified-Since"); since != client.lastModified {
+		t.Fatalf("Expected the request to be conditional on %s, got '%s'", client.lastModified, since)
+	}
+
+	// the api said the cached copy's the latest, so it's fresh again until the next publish
+	if body := readBody(t, cachingClient); body != "first" || client.calls != 2 {
+		t.Fatalf("Expected the cached 'first' after 2 calls, got '%s' after %d calls", body, client.calls)
+	}
+}
+
+func TestCachingClient_RefreshIsNotConditional(t *testing.T) {
+	dir := givenCacheDir(t)
+	clock := &fakeClock{time.Date(2021, 1, 10, 10, 0, 0, 0, time.UTC)}
+	client := &countingClient{body: "first", lastModified: "Sat, 09 Jan 2021 15:02:11 GMT"}
+
+	readBody(t, NewCachingClient(client, dir, clock, UseCache, logging.Discard()))
+	readBody(t, NewCachingClient(client, dir, clock, RefreshCache, logging.Discard()))
+
+	if since := client.header.Get("If-Modified-Since"); since != "" {
+		t.Fatalf("Expected refreshing to fetch whatever's cached, got a request conditional on %s", since)
+	}
+}
+
+func TestCachingClient_OfflineWithNothingCached(t *testing.T) {
+	client := &countingClient{body: "first"}
+	cachingClient := NewCachingClient(client, givenCacheDir(t), &fakeClock{time.Now()}, Offline, logging.Discard())
+
+	_, err := cachingClient.Get(context.Background(), "http://www.amireallyreal.com", nil)
+
+	if err != ErrNotCached || client.calls != 0 {
+		t.Fatalf("Expected ErrNotCached without calling the api, got %v after %d calls", err, client.calls)
+	}
+}
+
+func TestCachingClient_FallsBackToStaleCopyWhenApiFails(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"unreachable", errors.New("our data centre went bye bye"), 0},
+		{"server error", nil, http.StatusServiceUnavailable},
+	}
+
+	for _, test := range tests {
+		dir := givenCacheDir(t)
+		clock := &fakeClock{time.Date(2021, 1, 10, 10, 0, 0, 0, time.UTC)}
+		client := &countingClient{body: "first"}
+		var warnings bytes.Buffer
+		log := logging.New(&warnings, logging.Warn)
+		cachingClient := NewCachingClient(client, dir, clock, UseCache, log)
+
+		readBody(t, cachingClient)
+		client.body, client.err, client.status = "second", test.err, test.status
+		clock.now = clock.now.AddDate(0, 0, 2)
+
+		if body := readBody(t, cachingClient); body != "first" || client.calls != 2 {
+			t.Errorf("%s: expected the stale 'first' after 2 calls, got '%s' after %d calls", test.name, body, client.calls)
+		}
+
+		log.Flush()
+		if !strings.Contains(warnings.String(), "may be out of date: fetched ") {
+			t.Errorf("%s: expected a warning the figures are stale, got '%s'", test.name, warnings.String())
+		}
+	}
+}
+
+func TestCachingClient_RefreshDoesNotFallBackWhenApiFails(t *testing.T) {
+	dir := givenCacheDir(t)
+	clock := &fakeClock{time.Date(2021, 1, 10, 10, 0, 0, 0, time.UTC)}
+	client := &countingClient{body: "first"}
+
+	readBody(t, NewCachingClient(client, dir, clock, UseCache, logging.Discard()))
+	client.err = errors.New("our data centre went bye bye")
+
+	_, err := NewCachingClient(client, dir, clock, RefreshCache, logging.Discard()).Get(context.Background(), "http://www.amireallyreal.com", nil)
+
+	if err != client.err {
+		t.Fatalf("Expected the api's error rather than the cached copy, got %v", err)
+	}
+}
+
+func TestCachingClient_DoesNotFallBackWhenCancelled(t *testing.T) {
+	dir := givenCacheDir(t)
+	clock := &fakeClock{time.Date(2021, 1, 10, 10, 0, 0, 0, time.UTC)}
+	client := &countingClient{body: "first"}
+	cachingClient := NewCachingClient(client, dir, clock, UseCache, logging.Discard())
+
+	readBody(t, cachingClient)
+	client.err = context.Canceled
+	clock.now = clock.now.AddDate(0, 0, 2)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := cachingClient.Get(ctx, "http://www.amireallyreal.com", nil)
+
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected the cancellation rather than the stale copy, got %v", err)
+	}
+}
+
+func TestLastPublishTime_UsesUkTime(t *testing.T) {
+	// 4pm BST is 3pm UTC
+	summer := time.Date(2021, 6, 10, 15, 30, 0, 0, time.UTC)
+
+	published := lastPublishTime(summer)
+
+	if !published.Equal(time.Date(2021, 6, 10, 15, 0, 0, 0, time.UTC)) {
+		t.Fatalf("Expected 3pm UTC on the same day, got %v", published.UTC())
+	}
+}
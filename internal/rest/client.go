@@ -1,7 +1,34 @@
 package rest
 
-import "net/http"
+import (
+	"context"
+	"net/http"
+	"time"
+)
 
 type Client interface {
-	Get(url string) (resp *http.Response, err error)
+	// Get sends header, which can be nil, with the request, e.g. to make it conditional. It abandons
+	// the request as soon as ctx is cancelled
+	Get(ctx context.Context, url string, header http.Header) (resp *http.Response, err error)
+}
+
+type httpClient struct {
+	client *http.Client
+}
+
+// NewHttpClient fetches over http, giving up on a request that takes longer than timeout. A timeout
+// of 0 waits as long as the api takes
+func NewHttpClient(timeout time.Duration) Client {
+	return httpClient{&http.Client{Timeout: timeout}}
+}
+
+func (c httpClient) Get(ctx context.Context, url string, header http.Header) (resp *http.Response, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	return c.client.Do(req)
 }
@@ -0,0 +1,76 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// givenStalledServer never answers, until the test's over
+func givenStalledServer(t *testing.T, received chan<- struct{}) *httptest.Server {
+	stop := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- struct{}{}
+		select {
+		case <-r.Context().Done():
+		case <-stop:
+		}
+	}))
+	t.Cleanup(func() {
+		close(stop)
+		server.Close()
+	})
+	return server
+}
+
+func TestHttpClient_CancelsInFlightRequests(t *testing.T) {
+	received := make(chan struct{}, 1)
+	server := givenStalledServer(t, received)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-received
+		cancel()
+	}()
+
+	_, err := NewHttpClient(0).Get(ctx, server.URL, nil)
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the request to be cancelled, got %v", err)
+	}
+}
+
+func TestHttpClient_TimesOut(t *testing.T) {
+	server := givenStalledServer(t, make(chan struct{}, 1))
+
+	_, err := NewHttpClient(50*time.Millisecond).Get(context.Background(), server.URL, nil)
+
+	var netErr net.Error
+	if !errors.As(err, &netErr) || !netErr.Timeout() {
+		t.Errorf("Expected the request to time out, got %v", err)
+	}
+}
+
+func TestHttpClient_SendsTheHeader(t *testing.T) {
+	var since string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		since = r.Header.Get("If-Modified-Since")
+	}))
+	defer server.Close()
+
+	header := make(http.Header)
+	header.Set("If-Modified-Since", "Sat, 09 Jan 2021 15:02:11 GMT")
+	resp, err := NewHttpClient(0).Get(context.Background(), server.URL, header)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if since != header.Get("If-Modified-Since") {
+		t.Errorf("Expected the request to be sent with If-Modified-Since %s, got '%s'", header.Get("If-Modified-Since"), since)
+	}
+}
@@ -0,0 +1,153 @@
+package rest
+
+import (
+	"context"
+	"covid-stats-cli/internal/logging"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RetryPolicy says how long to wait between attempts. The waits double from InitialDelay up to
+// MaxDelay, unless the api says how long with a Retry-After header, and stop once the next wait would
+// take the time since the first attempt past MaxTotal
+type RetryPolicy struct {
+	InitialDelay time.Duration
+	MaxDelay     time.Duration
+	MaxTotal     time.Duration
+}
+
+// DefaultRetryPolicy rides out the few minutes the dashboard can be overloaded around publish time
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{InitialDelay: time.Second, MaxDelay: 30 * time.Second, MaxTotal: 2 * time.Minute}
+}
+
+// Sleeper waits between attempts, returning ctx's error if it's cancelled first
+type Sleeper interface {
+	Sleep(ctx context.Context, d time.Duration) error
+}
+
+type systemSleeper struct{}
+
+func (systemSleeper) Sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func SystemSleeper() Sleeper {
+	return systemSleeper{}
+}
+
+type retryingClient struct {
+	client  Client
+	policy  RetryPolicy
+	clock   Clock
+	sleeper Sleeper
+	log     *logging.Logger
+}
+
+// NewRetryingClient wraps client so requests that fail, or that the api answers with a 429 or 5xx,
+// are tried again. Each attempt is logged to log at debug level. When it gives up it returns the last
+// response or error
+func NewRetryingClient(client Client, policy RetryPolicy, clock Clock, sleeper Sleeper, log *logging.Logger) Client {
+	return retryingClient{client, policy, clock, sleeper, log}
+}
+
+func (c retryingClient) Get(ctx context.Context, url string, header http.Header) (resp *http.Response, err error) {
+	deadline := c.clock.Now().Add(c.policy.MaxTotal)
+
+	for attempt := 1; ; attempt++ {
+		resp, err = c.client.Get(ctx, url, header)
+		if !shouldRetry(resp, err) {
+			c.log.Debugf("attempt %d: GET %s: %s", attempt, url, outcome(resp, err))
+			return resp, err
+		}
+
+		wait, ok := retryAfter(resp, c.clock.Now())
+		if !ok {
+			wait = c.backoff(attempt)
+		}
+
+		if c.clock.Now().Add(wait).After(deadline) {
+			c.log.Debugf("attempt %d: GET %s: %s, giving up", attempt, url, outcome(resp, err))
+			return resp, err
+		}
+		c.log.Debugf("attempt %d: GET %s: %s, retrying in %s", attempt, url, outcome(resp, err), wait.Round(time.Millisecond))
+
+		if resp != nil {
+			resp.Body.Close()
+		}
+		if err := c.sleeper.Sleep(ctx, wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// backoff doubles the wait after each attempt, with up to half of it random so the clients that
+// failed together don't all try again together
+func (c retryingClient) backoff(attempt int) time.Duration {
+	wait := c.policy.MaxDelay
+	if attempt < 32 && c.policy.InitialDelay<<(attempt-1) < c.policy.MaxDelay {
+		wait = c.policy.InitialDelay << (attempt - 1)
+	}
+
+	return wait/2 + time.Duration(rand.Int63n(int64(wait/2)+1))
+}
+
+// shouldRetry is true for the failures that can go away by themselves: not reaching the api, being
+// rate limited and the api's own errors
+func shouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+}
+
+func outcome(resp *http.Response, err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	return fmt.Sprintf("%d %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+}
+
+// retryAfter is how long the response asks to be left before trying again, if it says
+func retryAfter(resp *http.Response, now time.Time) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+	return parseRetryAfter(resp.Header.Get("Retry-After"), now)
+}
+
+// parseRetryAfter reads a Retry-After header, which is either a number of seconds or a date
+func parseRetryAfter(header string, now time.Time) (time.Duration, bool) {
+	header = strings.TrimSpace(header)
+	if header == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(header); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	date, err := http.ParseTime(header)
+	if err != nil {
+		return 0, false
+	}
+	if date.Before(now) {
+		return 0, true
+	}
+	return date.Sub(now), true
+}
@@ -0,0 +1,219 @@
+package rest
+
+import (
+	"bytes"
+	"context"
+	"covid-stats-cli/internal/logging"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// scriptedClient answers each request with the next of its responses, or fails with the error at
+// the same position
+type scriptedClient struct {
+	statuses []int
+	errs     []error
+	headers  []http.Header
+	calls    int
+}
+
+func (c *scriptedClient) Get(_ context.Context, _ string, _ http.Header) (resp *http.Response, err error) {
+	i := c.calls
+	c.calls++
+	if i < len(c.errs) && c.errs[i] != nil {
+		return nil, c.errs[i]
+	}
+
+	header := make(http.Header)
+	if i < len(c.headers) && c.headers[i] != nil {
+		header = c.headers[i]
+	}
+	return &http.Response{
+		StatusCode: c.statuses[i],
+		Header:     header,
+		Body:       ioutil.NopCloser(bytes.NewBufferString("attempt " + strconv.Itoa(i+1))),
+	}, nil
+}
+
+// fakeSleeper moves the clock on instead of waiting, and records each wait
+type fakeSleeper struct {
+	clock *fakeClock
+	waits []time.Duration
+}
+
+func (s *fakeSleeper) Sleep(ctx context.Context, d time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.waits = append(s.waits, d)
+	s.clock.now = s.clock.now.Add(d)
+	return nil
+}
+
+var testPolicy = RetryPolicy{InitialDelay: time.Second, MaxDelay: 8 * time.Second, MaxTotal: time.Minute}
+
+func givenRetryingClient(client Client, policy RetryPolicy) (Client, *fakeSleeper, *bytes.Buffer) {
+	clock := &fakeClock{time.Date(2021, 1, 10, 16, 0, 0, 0, time.UTC)}
+	sleeper := &fakeSleeper{clock: clock}
+	var log bytes.Buffer
+	return NewRetryingClient(client, policy, clock, sleeper, logging.New(&log, logging.Debug)), sleeper, &log
+}
+
+func TestRetryingClient_RetriesServerErrorsWithBackoff(t *testing.T) {
+	client := &scriptedClient{statuses: []int{503, 500, 429, 200}}
+	retrying, sleeper, _ := givenRetryingClient(client, testPolicy)
+
+	body := readBody(t, retrying)
+
+	if body != "attempt 4" || client.calls != 4 {
+		t.Fatalf("Expected the 4th attempt to succeed, got '%s' after %d calls", body, client.calls)
+	}
+	for i, wait := range sleeper.waits {
+		max := testPolicy.InitialDelay << i
+		if wait < max/2 || wait > max {
+			t.Errorf("Expected wait %d to be between %s and %s, got %s", i+1, max/2, max, wait)
+		}
+	}
+}
+
+func TestRetryingClient_CapsTheBackoff(t *testing.T) {
+	client := &scriptedClient{statuses: []int{503, 503, 503, 503, 503, 503, 200}}
+	retrying, sleeper, _ := givenRetryingClient(client, testPolicy)
+
+	readBody(t, retrying)
+
+	for i, wait := range sleeper.waits {
+		if wait > testPolicy.MaxDelay {
+			t.Errorf("Expected wait %d to be at most %s, got %s", i+1, testPolicy.MaxDelay, wait)
+		}
+	}
+}
+
+func TestRetryingClient_RetriesNetworkErrors(t *testing.T) {
+	client := &scriptedClient{statuses: []int{0, 200}, errs: []error{errors.New("connection reset by peer")}}
+	retrying, _, _ := givenRetryingClient(client, testPolicy)
+
+	if body := readBody(t, retrying); body != "attempt 2" {
+		t.Fatalf("Expected the second attempt to succeed, got '%s'", body)
+	}
+}
+
+func TestRetryingClient_DoesNotRetryClientErrors(t *testing.T) {
+	for _, status := range []int{200, 204, 304, 400, 404} {
+		client := &scriptedClient{statuses: []int{status, 200}}
+		retrying, _, _ := givenRetryingClient(client, testPolicy)
+
+		resp, err := retrying.Get(context.Background(), "http://www.amireallyreal.com", nil)
+
+		if err != nil || resp.StatusCode != status || client.calls != 1 {
+			t.Errorf("Expected a %d to be returned straight away, got %v, %v after %d calls", status, resp, err, client.calls)
+		}
+	}
+}
+
+func TestRetryingClient_WaitsAsLongAsRetryAfterSays(t *testing.T) {
+	client := &scriptedClient{statuses: []int{429, 200}, headers: []http.Header{{"Retry-After": {"20"}}}}
+	retrying, sleeper, _ := givenRetryingClient(client, testPolicy)
+
+	readBody(t, retrying)
+
+	if len(sleeper.waits) != 1 || sleeper.waits[0] != 20*time.Second {
+		t.Fatalf("Expected a 20s wait, got %v", sleeper.waits)
+	}
+}
+
+func TestRetryingClient_GivesUpAfterTheMaxTotal(t *testing.T) {
+	client := &scriptedClient{statuses: []int{503, 503, 503, 503, 503, 503, 503, 503, 503, 503, 503, 503, 503, 503}}
+	policy := RetryPolicy{InitialDelay: time.Second, MaxDelay: 8 * time.Second, MaxTotal: 20 * time.Second}
+	retrying, sleeper, _ := givenRetryingClient(client, policy)
+
+	resp, err := retrying.Get(context.Background(), "http://www.amireallyreal.com", nil)
+
+	if err != nil || resp.StatusCode != 503 {
+		t.Fatalf("Expected the last 503 once it gave up, got %v, %v", resp, err)
+	}
+	var total time.Duration
+	for _, wait := range sleeper.waits {
+		total += wait
+	}
+	if total > policy.MaxTotal || client.calls == len(client.statuses) {
+		t.Fatalf("Expected to give up within %s, waited %s over %d calls", policy.MaxTotal, total, client.calls)
+	}
+}
+
+func TestRetryingClient_GivesUpWhenRetryAfterIsTooLong(t *testing.T) {
+	client := &scriptedClient{statuses: []int{429, 200}, headers: []http.Header{{"Retry-After": {"3600"}}}}
+	retrying, sleeper, _ := givenRetryingClient(client, testPolicy)
+
+	resp, _ := retrying.Get(context.Background(), "http://www.amireallyreal.com", nil)
+
+	if resp.StatusCode != 429 || len(sleeper.waits) != 0 {
+		t.Fatalf("Expected the 429 without waiting, got %d after waiting %v", resp.StatusCode, sleeper.waits)
+	}
+}
+
+func TestRetryingClient_StopsWhenCancelled(t *testing.T) {
+	client := &scriptedClient{statuses: []int{503, 200}}
+	retrying, _, _ := givenRetryingClient(client, testPolicy)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := retrying.Get(ctx, "http://www.amireallyreal.com", nil)
+
+	if !errors.Is(err, context.Canceled) || client.calls != 1 {
+		t.Fatalf("Expected the cancellation after 1 call, got %v after %d calls", err, client.calls)
+	}
+}
+
+func TestRetryingClient_LogsEachAttempt(t *testing.T) {
+	client := &scriptedClient{statuses: []int{503, 200}, headers: []http.Header{{"Retry-After": {"2"}}}}
+	retrying, _, log := givenRetryingClient(client, testPolicy)
+
+	readBody(t, retrying)
+
+	expected := "attempt 1: GET http://www.amireallyreal.com: 503 Service Unavailable, retrying in 2s\n" +
+		"attempt 2: GET http://www.amireallyreal.com: 200 OK\n"
+	if log.String() != expected {
+		t.Fatalf("Expected the log\n%s\ngot\n%s", expected, log.String())
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2021, 1, 10, 16, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		header   string
+		expected time.Duration
+		ok       bool
+	}{
+		{"", 0, false},
+		{"120", 2 * time.Minute, true},
+		{" 5 ", 5 * time.Second, true},
+		{"-1", 0, false},
+		{"Sun, 10 Jan 2021 16:01:30 GMT", 90 * time.Second, true},
+		{"Sun, 10 Jan 2021 15:59:00 GMT", 0, true},
+		{"soon", 0, false},
+	}
+
+	for _, test := range tests {
+		actual, ok := parseRetryAfter(test.header, now)
+		if actual != test.expected || ok != test.ok {
+			t.Errorf("Expected '%s' to be %s, %v, got %s, %v", test.header, test.expected, test.ok, actual, ok)
+		}
+	}
+}
+
+func TestSystemSleeper_StopsWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := SystemSleeper().Sleep(ctx, time.Hour)
+
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected the sleep to be cancelled, got %v", err)
+	}
+}
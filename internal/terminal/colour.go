@@ -0,0 +1,54 @@
+package terminal
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ColourMode says when to colour the output, like the --color flag of ls and grep
+type ColourMode string
+
+const (
+	// ColourAuto colours the output when it's a terminal and NO_COLOR isn't set
+	ColourAuto ColourMode = "auto"
+	// ColourAlways colours the output even when it's piped somewhere
+	ColourAlways ColourMode = "always"
+	// ColourNever never colours the output
+	ColourNever ColourMode = "never"
+)
+
+func ParseColourMode(mode string) (ColourMode, error) {
+	switch strings.ToLower(strings.TrimSpace(mode)) {
+	case "", "auto":
+		return ColourAuto, nil
+	case "always":
+		return ColourAlways, nil
+	case "never":
+		return ColourNever, nil
+	}
+
+	return "", fmt.Errorf("'%s' isn't a colour mode, choose one of: auto, always, never", mode)
+}
+
+// Colour works out whether to colour what's written to f. Always and never are taken at their
+// word, otherwise there's colour when f is a terminal that isn't dumb and NO_COLOR isn't set
+func Colour(mode ColourMode, f *os.File) bool {
+	switch mode {
+	case ColourAlways:
+		return true
+	case ColourNever:
+		return false
+	}
+
+	// see https://no-color.org
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+
+	if os.Getenv("TERM") == "dumb" {
+		return false
+	}
+
+	return isTerminal(f)
+}
@@ -0,0 +1,43 @@
+package terminal
+
+import "testing"
+
+func TestParseColourMode(t *testing.T) {
+	mode, err := ParseColourMode("Always")
+
+	if err != nil || mode != ColourAlways {
+		t.Fatalf("Expected %s but got %s and err %v", ColourAlways, mode, err)
+	}
+
+	if _, err := ParseColourMode("sometimes"); err == nil {
+		t.Fatalf("ParseColourMode() should return an error for a mode it doesn't know")
+	}
+}
+
+func TestColour_AlwaysAndNeverWin(t *testing.T) {
+	givenEnv(t, "NO_COLOR", "1")
+
+	if !Colour(ColourAlways, givenFile(t)) {
+		t.Fatalf("Expected always to colour even when NO_COLOR is set")
+	}
+
+	if Colour(ColourNever, givenFile(t)) {
+		t.Fatalf("Expected never to never colour")
+	}
+}
+
+func TestColour_AutoHonoursNoColor(t *testing.T) {
+	givenEnv(t, "NO_COLOR", "1")
+
+	if Colour(ColourAuto, givenFile(t)) {
+		t.Fatalf("Expected NO_COLOR to turn the colour off")
+	}
+}
+
+func TestColour_AutoIsOffWhenNotATerminal(t *testing.T) {
+	givenEnv(t, "NO_COLOR", "")
+
+	if Colour(ColourAuto, givenFile(t)) {
+		t.Fatalf("Expected no colour when the output is a file")
+	}
+}
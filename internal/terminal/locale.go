@@ -0,0 +1,18 @@
+package terminal
+
+import (
+	"os"
+	"strings"
+)
+
+// SupportsUnicode reports whether the locale says the terminal can show UTF-8. The first of
+// LC_ALL, LC_CTYPE and LANG that's set decides, the same as the C library
+func SupportsUnicode() bool {
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if locale := os.Getenv(name); locale != "" {
+			locale = strings.ToLower(locale)
+			return strings.Contains(locale, "utf-8") || strings.Contains(locale, "utf8")
+		}
+	}
+	return false
+}
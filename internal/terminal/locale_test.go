@@ -0,0 +1,33 @@
+package terminal
+
+import "testing"
+
+func givenLocale(t *testing.T, lcAll string, lcCtype string, lang string) {
+	givenEnv(t, "LC_ALL", lcAll)
+	givenEnv(t, "LC_CTYPE", lcCtype)
+	givenEnv(t, "LANG", lang)
+}
+
+func TestSupportsUnicode_Utf8Lang(t *testing.T) {
+	givenLocale(t, "", "", "en_GB.UTF-8")
+
+	if !SupportsUnicode() {
+		t.Fatalf("Expected en_GB.UTF-8 to support unicode")
+	}
+}
+
+func TestSupportsUnicode_LcAllWins(t *testing.T) {
+	givenLocale(t, "C", "", "en_GB.utf8")
+
+	if SupportsUnicode() {
+		t.Fatalf("Expected LC_ALL=C to override a UTF-8 LANG")
+	}
+}
+
+func TestSupportsUnicode_NoLocale(t *testing.T) {
+	givenLocale(t, "", "", "")
+
+	if SupportsUnicode() {
+		t.Fatalf("Expected no locale to mean ascii only")
+	}
+}
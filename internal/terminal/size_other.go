@@ -0,0 +1,17 @@
+//go:build !linux && !darwin && !freebsd && !netbsd && !openbsd
+// +build !linux,!darwin,!freebsd,!netbsd,!openbsd
+
+package terminal
+
+import "os"
+
+// size can't tell how wide the terminal is on this platform, so the default width is used
+func size(_ *os.File) (int, bool) {
+	return 0, false
+}
+
+// isTerminal guesses from the file being a character device, which a console is
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,38 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd
+// +build linux darwin freebsd netbsd openbsd
+
+package terminal
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+type winsize struct {
+	rows    uint16
+	columns uint16
+	x       uint16
+	y       uint16
+}
+
+// size asks the terminal how many columns it has. It's not ok when f isn't a terminal
+func size(f *os.File) (int, bool) {
+	ws, ok := windowSize(f)
+	if !ok || ws.columns == 0 {
+		return 0, false
+	}
+	return int(ws.columns), true
+}
+
+// isTerminal is true when f answers the window size ioctl, which only terminals do
+func isTerminal(f *os.File) bool {
+	_, ok := windowSize(f)
+	return ok
+}
+
+func windowSize(f *os.File) (winsize, bool) {
+	var ws winsize
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(), uintptr(syscall.TIOCGWINSZ), uintptr(unsafe.Pointer(&ws)))
+	return ws, errno == 0
+}
@@ -0,0 +1,27 @@
+package terminal
+
+import (
+	"os"
+	"strconv"
+)
+
+// DefaultWidth is used when the output isn't a terminal and nothing says how wide it should be
+const DefaultWidth = 80
+
+// Width works out how many columns the output has to fit in. An override, e.g. from a --width flag,
+// wins, then the COLUMNS environment variable, then the size of the terminal f is attached to
+func Width(override int, f *os.File) int {
+	if override > 0 {
+		return override
+	}
+
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 {
+		return columns
+	}
+
+	if columns, ok := size(f); ok {
+		return columns
+	}
+
+	return DefaultWidth
+}
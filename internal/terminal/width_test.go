@@ -0,0 +1,64 @@
+package terminal
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func givenColumns(t *testing.T, columns string) {
+	givenEnv(t, "COLUMNS", columns)
+}
+
+// givenEnv sets the environment variable for the test, an empty value unsets it
+func givenEnv(t *testing.T, name string, value string) {
+	original, set := os.LookupEnv(name)
+	if value == "" {
+		os.Unsetenv(name)
+	} else {
+		os.Setenv(name, value)
+	}
+	t.Cleanup(func() {
+		if set {
+			os.Setenv(name, original)
+		} else {
+			os.Unsetenv(name)
+		}
+	})
+}
+
+func givenFile(t *testing.T) *os.File {
+	f, err := ioutil.TempFile("", "not-a-terminal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		f.Close()
+		os.Remove(f.Name())
+	})
+	return f
+}
+
+func TestWidth_OverrideWins(t *testing.T) {
+	givenColumns(t, "100")
+
+	if width := Width(60, givenFile(t)); width != 60 {
+		t.Fatalf("Expected the override of 60 but got %d", width)
+	}
+}
+
+func TestWidth_UsesColumns(t *testing.T) {
+	givenColumns(t, "100")
+
+	if width := Width(0, givenFile(t)); width != 100 {
+		t.Fatalf("Expected COLUMNS of 100 but got %d", width)
+	}
+}
+
+func TestWidth_DefaultsWhenNotATerminal(t *testing.T) {
+	givenColumns(t, "lots")
+
+	if width := Width(0, givenFile(t)); width != DefaultWidth {
+		t.Fatalf("Expected the default width %d but got %d", DefaultWidth, width)
+	}
+}
@@ -1,215 +1,83 @@
 package main
 
 import (
-	"bufio"
+	"context"
 	"covid-stats-cli/internal/coviddata"
+	"covid-stats-cli/internal/logging"
+	"covid-stats-cli/internal/rest"
+	"covid-stats-cli/internal/terminal"
+	"flag"
 	"fmt"
-	"net/http"
 	"os"
-	"strings"
+	"os/signal"
 )
 
 func main() {
-	api := coviddata.NewCovidDataRestApi(covidApiUrl(), http.DefaultClient)
-	covidDataHandler := coviddata.NewHandler(api)
-
-	printIntroTitle()
+	cfg := config{timeout: defaultTimeout}
+	cfg.register(flag.CommandLine)
+	flag.Usage = func() {
+		printUsage(os.Stderr)
+	}
+	flag.Parse()
 
-	userInput := make(chan string)
-	go listenForInput(userInput)
+	if err := cfg.validate(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(exitUsage)
+	}
 
-	for {
-		fmt.Println("Type:")
-		fmt.Println()
-		fmt.Println("- d for deaths")
-		fmt.Println("- c for cases")
-		fmt.Println()
-		fmt.Print("> ")
+	if flag.NArg() > 0 {
+		os.Exit(runCommand(context.Background(), flag.Args(), cfg, newClient, os.Stdout, os.Stderr))
+	}
 
-		select {
-		case input := <-userInput:
-			if input == "d" {
-				printDeathsMenu()
-				fmt.Print("> ")
-				select {
-				case furtherInput := <-userInput:
-					fmt.Println()
-					printDeathStats(furtherInput, covidDataHandler)
-				}
-			} else if input == "c" {
-				printCasesMenu()
-				fmt.Print("> ")
-				select {
-				case furtherInput := <-userInput:
-					fmt.Println()
-					printCaseStats(furtherInput, covidDataHandler)
-				}
-			} else {
-				fmt.Printf("'%s' isn't really something I offered, is it? :) \n\n", input)
-			}
-		}
+	area := coviddata.England
+	log := cfg.logger(os.Stderr)
+	covidDataHandler, err := newHandler(area, cfg, newClient(cfg, log), log)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(exitUsage)
 	}
-}
 
-func printIntroTitle() {
-	fmt.Println()
-	fmt.Println("Ready for some anxiety? Awesome! Anxiety for everyone!❤️")
-	fmt.Println()
-	fmt.Println("Here you can check all the latest COVID trends....")
-	fmt.Println(".... never again question whether it's time to start hating on the Tories and your fellow man")
-	fmt.Println()
-	fmt.Println("**NOTE** this tool is for viewing COVID trends, not the raw figures. You'll find no raw data" +
-		" in these parts.")
-	fmt.Println()
-	fmt.Println("<------- Enjoy :) :) -------> ")
-	fmt.Println()
+	// Ctrl-C stops a fetch and goes back to the menu, or quits when the menu's waiting for a choice
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt)
+
+	os.Exit(runMenu(context.Background(), os.Stdin, os.Stdout, interrupts, covidDataHandler, area, log))
 }
 
-func listenForInput(input chan<- string) {
-	for {
-		buf := bufio.NewReader(os.Stdin)
-		bytes, err := buf.ReadBytes('\n')
-		if err != nil {
-			panic(err)
-		}
+// clientFactory makes the client the api is fetched with, once the flags saying how have been parsed
+type clientFactory func(cfg config, log *logging.Logger) rest.Client
 
-		input <- strings.TrimSpace(string(bytes))
+func newHandler(area coviddata.Area, cfg config, client rest.Client, log *logging.Logger) (*coviddata.Handler, error) {
+	populations, err := cfg.populations()
+	if err != nil {
+		return nil, err
 	}
-}
 
-func printCaseStats(input string, handler *coviddata.Handler) {
-	switch input {
-	case "w":
-		fmt.Println("Fetching cases for the last 1 weeks")
-		stats, err := handler.GetCasesChart(1)
-		if err != nil {
-			fmt.Printf("Error fetching the case stats: %+v\n", err)
-		} else {
-			fmt.Println(stats)
-		}
-	case "ww":
-		fmt.Println("Fetching cases for the last 2 weeks")
-		stats, err := handler.GetCasesChart(2)
-		if err != nil {
-			fmt.Printf("Error fetching the case stats: %+v\n", err)
-		} else {
-			fmt.Println(stats)
-		}
-	case "www":
-		fmt.Println("Fetching cases for the last 3 weeks")
-		stats, err := handler.GetCasesChart(3)
-		if err != nil {
-			fmt.Printf("Error fetching the case stats: %+v\n", err)
-		} else {
-			fmt.Println(stats)
-		}
-	case "m":
-		fmt.Println("Fetching cases for the last 4 weeks")
-		stats, err := handler.GetCasesChart(4)
-		if err != nil {
-			fmt.Printf("Error fetching the case stats: %+v\n", err)
-		} else {
-			fmt.Println(stats)
-		}
-	case "mm":
-		fmt.Println("Fetching cases for the last 8 weeks")
-		stats, err := handler.GetCasesChart(8)
-		if err != nil {
-			fmt.Printf("Error fetching the case stats: %+v\n", err)
-		} else {
-			fmt.Println(stats)
-		}
-	case "mmm":
-		fmt.Println("Fetching cases for the last 12 weeks")
-		stats, err := handler.GetCasesChart(12)
-		if err != nil {
-			fmt.Printf("Error fetching the case stats: %+v\n", err)
-		} else {
-			fmt.Println(stats)
-		}
-	default:
-		fmt.Printf("'%s' is not a valid option mmmm'kay.....\n", input)
-	}
+	api := coviddata.NewCovidDataRestApi(covidApiUrl(), area, client, log)
+	handler := coviddata.NewHandler(api, log)
+	handler.SetPopulations(populations)
+	handler.SetWidth(terminal.Width(cfg.width, os.Stdout))
+	handler.SetChartStyle(cfg.chartStyle())
+	handler.SetGapPolicy(cfg.gapPolicy())
+	handler.SetUnicode(!cfg.ascii && terminal.SupportsUnicode())
+	handler.SetColour(terminal.Colour(cfg.colourMode(), os.Stdout))
+	return handler, nil
 }
 
-func printDeathStats(input string, handler *coviddata.Handler) {
-	switch input {
-	case "w":
-		fmt.Println("Fetching deaths stats for the last 1 weeks...")
-		stats, err := handler.GetDeathsChart(1)
-		if err != nil {
-			fmt.Printf("Error fetching the death stats: %+v\n", err)
-		} else {
-			fmt.Println(stats)
-		}
-	case "ww":
-		fmt.Println("Fetching deaths stats for the last 2 weeks...")
-		stats, err := handler.GetDeathsChart(2)
-		if err != nil {
-			fmt.Printf("Error fetching the death stats: %+v\n", err)
-		} else {
-			fmt.Println(stats)
-		}
-	case "www":
-		fmt.Println("Fetching deaths stats for the last 3 weeks...")
-		stats, err := handler.GetDeathsChart(3)
-		if err != nil {
-			fmt.Printf("Error fetching the death stats: %+v\n", err)
-		} else {
-			fmt.Println(stats)
-		}
-	case "m":
-		fmt.Println("Fetching deaths stats for the last 4 weeks...")
-		stats, err := handler.GetDeathsChart(4)
-		if err != nil {
-			fmt.Printf("Error fetching the death stats: %+v\n", err)
-		} else {
-			fmt.Println(stats)
-		}
-	case "mm":
-		fmt.Println("Fetching deaths stats for the last 8 weeks...")
-		stats, err := handler.GetDeathsChart(8)
-		if err != nil {
-			fmt.Printf("Error fetching the death stats: %+v\n", err)
-		} else {
-			fmt.Println(stats)
-		}
-	case "mmm":
-		fmt.Println("Fetching deaths stats for the last 12 weeks...")
-		stats, err := handler.GetDeathsChart(12)
-		if err != nil {
-			fmt.Printf("Error fetching the death stats: %+v\n", err)
-		} else {
-			fmt.Println(stats)
-		}
-	default:
-		fmt.Printf("'%s' is not a valid option mmmm'kay.....\n", input)
-	}
-}
+func newClient(cfg config, log *logging.Logger) rest.Client {
+	// the dashboard is often overloaded around publish time, so failed requests are tried again
+	client := rest.NewRetryingClient(rest.NewHttpClient(cfg.timeout), rest.DefaultRetryPolicy(), rest.SystemClock(),
+		rest.SystemSleeper(), log)
 
-func printCasesMenu() {
-	fmt.Println()
-	fmt.Println("- w for the last weeks' cases")
-	fmt.Println("- ww for the last twos' cases")
-	fmt.Println("- www for the last three weeks' cases")
-	fmt.Println("- m for the last four weeks' cases")
-	fmt.Println("- mm for the last eight weeks' cases")
-	fmt.Println("- mmm for the last twelve weeks' cases")
-	fmt.Println()
-}
+	cacheDir, err := rest.DefaultCacheDir()
+	if err != nil {
+		log.Warnf("Can't cache the data, there's no cache directory: %v", err)
+		return client
+	}
 
-func printDeathsMenu() {
-	fmt.Println()
-	fmt.Println("- w for the last weeks' deaths")
-	fmt.Println("- ww for the last twos' deaths")
-	fmt.Println("- www for the last three weeks' deaths")
-	fmt.Println("- m for the last four weeks' deaths")
-	fmt.Println("- mm for the last eight weeks' deaths")
-	fmt.Println("- mmm for the last twelve weeks' deaths")
-	fmt.Println()
+	return rest.NewCachingClient(client, cacheDir, rest.SystemClock(), cfg.cacheMode(), log)
 }
 
 func covidApiUrl() string {
-	return "https://api.coronavirus.data.gov.uk/v1/data?filters=areaType=nation;areaName=england" +
-		"&structure={\"date\":\"date\",\"cases\":\"newCasesByPublishDate\",\"deaths\":\"newDeaths28DaysByPublishDate\"}"
+	return "https://api.coronavirus.data.gov.uk/v1/data"
 }
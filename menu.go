@@ -0,0 +1,378 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"covid-stats-cli/internal/coviddata"
+	"covid-stats-cli/internal/logging"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// menu is the interactive prompt. It reads the user's choices from lines and writes the charts to out
+type menu struct {
+	ctx     context.Context
+	lines   <-chan string
+	out     io.Writer
+	handler *coviddata.Handler
+	// query has the area and the settings the user's chosen, each chart fills in the rest
+	query coviddata.Query
+	log   *logging.Logger
+	// quit is closed when the user interrupts while nothing's being fetched
+	quit chan struct{}
+	// mu guards cancelFetch, which stops the fetch in progress, if there is one
+	mu          sync.Mutex
+	cancelFetch context.CancelFunc
+}
+
+// runMenu shows the menu until the input ends, the user types q or quit, or ctx is cancelled. An
+// interrupt, i.e. Ctrl-C, stops whatever's being fetched, or quits when nothing is. It returns the
+// status code the process should exit with. The warnings log collects about the data are written
+// after each chart
+func runMenu(ctx context.Context, in io.Reader, out io.Writer, interrupts <-chan os.Signal, handler *coviddata.Handler,
+	area coviddata.Area, log *logging.Logger) int {
+	lines := make(chan string)
+	go readLines(ctx, in, lines)
+
+	m := &menu{ctx: ctx, lines: lines, out: out, handler: handler, log: log, quit: make(chan struct{}),
+		query: coviddata.Query{Area: area, DateBasis: coviddata.PublishDate}}
+	go m.watchInterrupts(interrupts)
+	m.printIntroTitle()
+	m.loop()
+
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "Bye! Stay safe :)")
+	return exitOk
+}
+
+// readLines sends every line of in, trimmed, until the input ends or ctx is cancelled. It uses
+// the one reader throughout so nothing it's buffered is lost between lines
+func readLines(ctx context.Context, in io.Reader, lines chan<- string) {
+	defer close(lines)
+
+	reader := bufio.NewReader(in)
+	for {
+		line, err := reader.ReadString('\n')
+		// the last line can be missing its newline
+		if err == nil || line != "" {
+			select {
+			case lines <- strings.TrimSpace(line):
+			case <-ctx.Done():
+				return
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// watchInterrupts is the only reader of interrupts, so whether one stops a fetch or quits is decided
+// in one place. An interrupt stops the fetch in progress, or closes quit when nothing's being fetched
+func (m *menu) watchInterrupts(interrupts <-chan os.Signal) {
+	for {
+		select {
+		case <-m.ctx.Done():
+			return
+		case <-interrupts:
+		}
+
+		m.mu.Lock()
+		cancel := m.cancelFetch
+		m.cancelFetch = nil
+		m.mu.Unlock()
+
+		if cancel == nil {
+			close(m.quit)
+			return
+		}
+		cancel()
+	}
+}
+
+// read waits for the user's next line. It's false when there's nothing more to do: the input's
+// ended, the user's quit or interrupted, or the context's been cancelled
+func (m *menu) read() (string, bool) {
+	select {
+	case <-m.ctx.Done():
+		return "", false
+	case <-m.quit:
+		return "", false
+	case line, ok := <-m.lines:
+		if !ok || line == "q" || line == "quit" {
+			return "", false
+		}
+		return line, true
+	}
+}
+
+// fetchContext is cancelled by an interrupt, which abandons the fetch rather than the menu, until
+// the returned cancel is called
+func (m *menu) fetchContext() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(m.ctx)
+
+	m.mu.Lock()
+	m.cancelFetch = cancel
+	m.mu.Unlock()
+
+	return ctx, func() {
+		m.mu.Lock()
+		m.cancelFetch = nil
+		m.mu.Unlock()
+		cancel()
+	}
+}
+
+// printFetchError says why the stats couldn't be fetched, e.g. "case stats"
+func (m *menu) printFetchError(stats string, err error) {
+	if errors.Is(err, context.Canceled) {
+		fmt.Fprintf(m.out, "Stopped fetching the %s\n\n", stats)
+		return
+	}
+	message, _ := explain(err)
+	fmt.Fprintf(m.out, "Couldn't fetch the %s: %s\n", stats, message)
+}
+
+func (m *menu) loop() {
+	for {
+		m.printOptions()
+
+		input, ok := m.read()
+		if !ok {
+			return
+		}
+
+		if input == "d" {
+			if !m.chart(coviddata.Deaths) {
+				return
+			}
+		} else if input == "c" {
+			if !m.chart(coviddata.Cases) {
+				return
+			}
+		} else if input == "m" {
+			metric, ok := m.selectMetric()
+			if !ok {
+				return
+			}
+			if metric != nil && !m.chart(*metric) {
+				return
+			}
+		} else if input == "a" {
+			area, ok := m.selectArea()
+			if !ok {
+				return
+			}
+			m.query.Area = area
+		} else if input == "p" {
+			m.query.Per100k = !m.query.Per100k
+			fmt.Fprintf(m.out, "Figures per 100k people are now %s\n\n", onOrOff(m.query.Per100k))
+		} else if input == "b" {
+			if m.query.DateBasis == coviddata.PublishDate {
+				m.query.DateBasis = coviddata.EventDate
+				fmt.Fprintln(m.out, "Charting by specimen date/date of death. The most recent days are still being reported")
+				fmt.Fprintln(m.out, "Metrics without either are still charted by publish date")
+			} else {
+				m.query.DateBasis = coviddata.PublishDate
+				fmt.Fprintln(m.out, "Charting by publish date")
+			}
+			fmt.Fprintln(m.out)
+		} else if input == "v" {
+			m.handler.SetChartStyle(nextChartStyle(m.handler.ChartStyle()))
+			fmt.Fprintf(m.out, "The charts are now drawn %s\n\n", m.handler.ChartStyle())
+		} else if input == "g" {
+			m.handler.SetGapPolicy(nextGapPolicy(m.handler.GapPolicy()))
+			fmt.Fprintf(m.out, "Missing figures are now charted with the %s policy\n\n", m.handler.GapPolicy())
+		} else if input == "r" {
+			if m.query.RollingWindow == 0 {
+				m.query.RollingWindow = 7
+			} else {
+				m.query.RollingWindow = 0
+			}
+			fmt.Fprintf(m.out, "The 7-day rolling average is now %s\n\n", onOrOff(m.query.RollingWindow != 0))
+		} else {
+			fmt.Fprintf(m.out, "'%s' isn't really something I offered, is it? :) \n\n", input)
+		}
+	}
+}
+
+func (m *menu) printOptions() {
+	fmt.Fprintln(m.out, "Type:")
+	fmt.Fprintln(m.out)
+	fmt.Fprintln(m.out, "- d for deaths")
+	fmt.Fprintln(m.out, "- c for cases")
+	fmt.Fprintln(m.out, "- m for other metrics, e.g. hospital admissions")
+	fmt.Fprintf(m.out, "- a to change the area (currently %s)\n", m.query.Area)
+	fmt.Fprintf(m.out, "- r to turn the 7-day rolling average %s\n", onOrOff(m.query.RollingWindow == 0))
+	fmt.Fprintf(m.out, "- p to turn figures per 100k people %s\n", onOrOff(!m.query.Per100k))
+	if m.query.DateBasis == coviddata.PublishDate {
+		fmt.Fprintln(m.out, "- b to chart by specimen date/date of death instead of publish date")
+	} else {
+		fmt.Fprintln(m.out, "- b to chart by publish date instead of specimen date/date of death")
+	}
+	fmt.Fprintf(m.out, "- v to change the chart style (currently %s)\n", m.handler.ChartStyle())
+	fmt.Fprintf(m.out, "- g to change how missing figures are charted (currently %s)\n", m.handler.GapPolicy())
+	fmt.Fprintln(m.out, "- q to quit")
+	fmt.Fprintln(m.out)
+	fmt.Fprint(m.out, "> ")
+}
+
+func (m *menu) printIntroTitle() {
+	fmt.Fprintln(m.out)
+	fmt.Fprintln(m.out, "Ready for some anxiety? Awesome! Anxiety for everyone!❤️")
+	fmt.Fprintln(m.out)
+	fmt.Fprintln(m.out, "Here you can check all the latest COVID trends....")
+	fmt.Fprintln(m.out, ".... never again question whether it's time to start hating on the Tories and your fellow man")
+	fmt.Fprintln(m.out)
+	fmt.Fprintln(m.out, "**NOTE** this menu is for viewing COVID trends. For the raw figures, run a metric with"+
+		" --output json, csv or tsv, e.g. covid-stats-cli cases --output csv")
+	fmt.Fprintln(m.out)
+	fmt.Fprintln(m.out, "<------- Enjoy :) :) -------> ")
+	fmt.Fprintln(m.out)
+}
+
+// selectMetric asks which metric to chart. The metric is nil when the user didn't pick one, and
+// it's false when there's no more input
+func (m *menu) selectMetric() (*coviddata.Metric, bool) {
+	fmt.Fprintln(m.out)
+	fmt.Fprintln(m.out, "Which metric?")
+	fmt.Fprintln(m.out)
+	for _, metric := range coviddata.Metrics {
+		fmt.Fprintf(m.out, "- %s for %s\n", metric.Name, strings.ToLower(metric.Title))
+	}
+	fmt.Fprintln(m.out)
+	fmt.Fprint(m.out, "> ")
+
+	input, ok := m.read()
+	if !ok {
+		return nil, false
+	}
+
+	metric, err := coviddata.MetricByName(input)
+	if err != nil {
+		fmt.Fprintf(m.out, "%v\n\n", err)
+		return nil, true
+	}
+
+	return &metric, true
+}
+
+// the number of weeks each menu option charts
+var menuWeeks = map[string]int{"w": 1, "ww": 2, "www": 3, "m": 4, "mm": 8, "mmm": 12}
+
+// chart asks how many weeks of the metric to chart, then charts them. It's false when there's no
+// more input
+func (m *menu) chart(metric coviddata.Metric) bool {
+	m.printMetricMenu(metric)
+	fmt.Fprint(m.out, "> ")
+	input, ok := m.read()
+	if !ok {
+		return false
+	}
+	fmt.Fprintln(m.out)
+
+	weeks, ok := menuWeeks[input]
+	if !ok {
+		fmt.Fprintf(m.out, "'%s' is not a valid option mmmm'kay.....\n", input)
+		return true
+	}
+	query := m.query
+	query.Metric, query.PreviousDays = metric, weeks*7
+	query.Render = []coviddata.Renderer{coviddata.RenderChart, coviddata.RenderSummary}
+	m.printStats(query)
+	return true
+}
+
+// printStats runs the query for the chart and the summary underneath it
+func (m *menu) printStats(query coviddata.Query) {
+	ctx, cancel := m.fetchContext()
+	defer cancel()
+	defer m.log.Flush()
+
+	fmt.Fprintf(m.out, "Fetching %s for the last %d weeks...\n", strings.ToLower(query.Metric.Title), query.PreviousDays/7)
+	result, err := m.handler.Run(ctx, query)
+	if err != nil {
+		m.printFetchError(query.Metric.Name+" stats", err)
+		return
+	}
+	fmt.Fprintln(m.out, result.Chart)
+	fmt.Fprintln(m.out, result.Summary)
+}
+
+func (m *menu) printMetricMenu(metric coviddata.Metric) {
+	title := strings.ToLower(metric.Title)
+	fmt.Fprintln(m.out)
+	fmt.Fprintf(m.out, "- w for the last weeks' %s\n", title)
+	fmt.Fprintf(m.out, "- ww for the last two weeks' %s\n", title)
+	fmt.Fprintf(m.out, "- www for the last three weeks' %s\n", title)
+	fmt.Fprintf(m.out, "- m for the last four weeks' %s\n", title)
+	fmt.Fprintf(m.out, "- mm for the last eight weeks' %s\n", title)
+	fmt.Fprintf(m.out, "- mmm for the last twelve weeks' %s\n", title)
+	fmt.Fprintln(m.out)
+}
+
+// selectArea asks for the area to show, sticking with the current one when the answer isn't an
+// area. It's false when there's no more input
+func (m *menu) selectArea() (coviddata.Area, bool) {
+	fmt.Fprintln(m.out)
+	fmt.Fprintln(m.out, "Which type of area?")
+	fmt.Fprintln(m.out)
+	for _, areaType := range coviddata.AreaTypes {
+		fmt.Fprintf(m.out, "- %s\n", areaType)
+	}
+	fmt.Fprintln(m.out)
+	fmt.Fprint(m.out, "> ")
+	areaType, ok := m.read()
+	if !ok {
+		return m.query.Area, false
+	}
+
+	var name string
+	if areaType != string(coviddata.Overview) {
+		fmt.Fprintln(m.out)
+		fmt.Fprint(m.out, "Which area? (e.g. London, Manchester) > ")
+		if name, ok = m.read(); !ok {
+			return m.query.Area, false
+		}
+	}
+
+	area, err := coviddata.NewArea(areaType, name)
+	if err != nil {
+		fmt.Fprintf(m.out, "%v. Sticking with %s\n\n", err, m.query.Area)
+		return m.query.Area, true
+	}
+
+	fmt.Fprintf(m.out, "Showing stats for %s\n\n", area)
+	return area, true
+}
+
+// nextChartStyle cycles through the styles, back to the first after the last
+func nextChartStyle(style coviddata.ChartStyle) coviddata.ChartStyle {
+	for i, s := range coviddata.ChartStyles {
+		if s == style {
+			return coviddata.ChartStyles[(i+1)%len(coviddata.ChartStyles)]
+		}
+	}
+	return coviddata.ChartStyles[0]
+}
+
+// nextGapPolicy cycles through the gap policies, back to the first after the last
+func nextGapPolicy(policy coviddata.GapPolicy) coviddata.GapPolicy {
+	for i, p := range coviddata.GapPolicies {
+		if p == policy {
+			return coviddata.GapPolicies[(i+1)%len(coviddata.GapPolicies)]
+		}
+	}
+	return coviddata.GapPolicies[0]
+}
+
+func onOrOff(on bool) string {
+	if on {
+		return "on"
+	}
+	return "off"
+}
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"covid-stats-cli/internal/coviddata"
+	"covid-stats-cli/internal/logging"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeClient answers every request with the same week of deaths and cases, except the deaths
+// missingDeaths days ago. A stalled one never answers, telling received about each request and
+// waiting for it to be cancelled. One with a status answers every request with it and no figures
+type fakeClient struct {
+	calls         int
+	missingDeaths int
+	stalled       bool
+	received      chan struct{}
+	status        int
+}
+
+func (c *fakeClient) Get(ctx context.Context, _ string, _ http.Header) (resp *http.Response, err error) {
+	c.calls++
+	if c.stalled {
+		c.received <- struct{}{}
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	if c.status != 0 {
+		return &http.Response{StatusCode: c.status, Body: ioutil.NopCloser(strings.NewReader(""))}, nil
+	}
+
+	var entries []string
+	for day := 1; day <= 7; day++ {
+		date := time.Now().AddDate(0, 0, -day).Format("2006-01-02")
+		if day == c.missingDeaths {
+			entries = append(entries, fmt.Sprintf("{\"date\":\"%s\",\"cases\":%d}", date, day*100))
+		} else {
+			entries = append(entries, fmt.Sprintf("{\"date\":\"%s\",\"cases\":%d,\"deaths\":%d}", date, day*100, day))
+		}
+	}
+
+	body := "{\"data\":[" + strings.Join(entries, ",") + "],\"pagination\":{\"next\":null}}"
+	return &http.Response{StatusCode: 200, Body: ioutil.NopCloser(strings.NewReader(body))}, nil
+}
+
+func givenMenuHandler(client *fakeClient, log *logging.Logger) *coviddata.Handler {
+	api := coviddata.NewCovidDataRestApi("http://www.amireallyreal.com/v1/data", coviddata.England, client, log)
+	handler := coviddata.NewHandler(api, log)
+	handler.SetWidth(80)
+	return handler
+}
+
+func runMenuWith(t *testing.T, ctx context.Context, in io.Reader) (string, *fakeClient) {
+	return runMenuWithClient(t, ctx, in, make(chan os.Signal), &fakeClient{})
+}
+
+func runMenuWithClient(t *testing.T, ctx context.Context, in io.Reader, interrupts <-chan os.Signal,
+	client *fakeClient) (string, *fakeClient) {
+	// the warnings go to the same place as the charts, to check they come after them
+	var out bytes.Buffer
+	log := logging.New(&out, logging.Info)
+
+	done := make(chan int)
+	go func() {
+		done <- runMenu(ctx, in, &out, interrupts, givenMenuHandler(client, log), coviddata.England, log)
+	}()
+
+	select {
+	case code := <-done:
+		if code != exitOk {
+			t.Errorf("Expected the menu to exit with %d, got %d", exitOk, code)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("The menu didn't exit")
+	}
+
+	return out.String(), client
+}
+
+func TestRunMenu_ExitsAtTheEndOfTheInput(t *testing.T) {
+	out, _ := runMenuWith(t, context.Background(), strings.NewReader(""))
+
+	if !strings.HasSuffix(out, "Bye! Stay safe :)\n") {
+		t.Errorf("Expected a goodbye at the end of the input, got %s", out)
+	}
+}
+
+func TestRunMenu_ExitsWhenTheInputEndsPartWayThrough(t *testing.T) {
+	out, client := runMenuWith(t, context.Background(), strings.NewReader("d\n"))
+
+	if !strings.Contains(out, "- w for the last weeks' new deaths") {
+		t.Errorf("Expected the deaths menu, got %s", out)
+	}
+	if client.calls != 0 {
+		t.Errorf("Expected nothing to be fetched, got %d requests", client.calls)
+	}
+}
+
+func TestRunMenu_QuitsOnQOrQuit(t *testing.T) {
+	for _, input := range []string{"q", "quit", " quit "} {
+		out, client := runMenuWith(t, context.Background(), strings.NewReader(input+"\nd\nw\n"))
+
+		if strings.Contains(out, "- w for the last weeks' new deaths") || client.calls != 0 {
+			t.Errorf("Expected '%s' to quit before the rest of the input, got %s", input, out)
+		}
+	}
+}
+
+func TestRunMenu_ExitsWhenCancelled(t *testing.T) {
+	// the input never ends, like a terminal no one's typing in
+	in, writer := io.Pipe()
+	defer writer.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	out, _ := runMenuWith(t, ctx, in)
+
+	if !strings.HasSuffix(out, "Bye! Stay safe :)\n") {
+		t.Errorf("Expected a goodbye when cancelled, got %s", out)
+	}
+}
+
+func TestRunMenu_ChartsDeaths(t *testing.T) {
+	out, client := runMenuWith(t, context.Background(), strings.NewReader("d\nw\n"))
+
+	if !strings.Contains(out, "Fetching new deaths for the last 1 weeks...") {
+		t.Errorf("Expected the deaths to be fetched, got %s", out)
+	}
+	if strings.Contains(out, "Error") {
+		t.Errorf("Expected no errors, got %s", out)
+	}
+	// the chart and its summary come from the same request
+	if client.calls != 1 {
+		t.Errorf("Expected the deaths to be requested once, got %d requests", client.calls)
+	}
+}
+
+func TestRunMenu_ReadsEveryLineInOrder(t *testing.T) {
+	// the last line has no newline
+	out, _ := runMenuWith(t, context.Background(), strings.NewReader("p\nr\nhuh"))
+
+	per100k := strings.Index(out, "Figures per 100k people are now on")
+	rolling := strings.Index(out, "The 7-day rolling average is now on")
+	unknown := strings.Index(out, "'huh' isn't really something I offered")
+	if per100k == -1 || rolling < per100k || unknown < rolling {
+		t.Errorf("Expected every line to be answered in order, got %s", out)
+	}
+}
+
+func TestRunMenu_QuitsWhenInterruptedAtThePrompt(t *testing.T) {
+	in, writer := io.Pipe()
+	defer writer.Close()
+
+	interrupts := make(chan os.Signal, 1)
+	interrupts <- os.Interrupt
+
+	out, _ := runMenuWithClient(t, context.Background(), in, interrupts, &fakeClient{})
+
+	if !strings.HasSuffix(out, "Bye! Stay safe :)\n") {
+		t.Errorf("Expected a goodbye when interrupted, got %s", out)
+	}
+}
+
+func TestRunMenu_InterruptingAFetchGoesBackToTheMenu(t *testing.T) {
+	client := &fakeClient{stalled: true, received: make(chan struct{})}
+	interrupts := make(chan os.Signal)
+	go func() {
+		<-client.received
+		interrupts <- os.Interrupt
+	}()
+
+	out, _ := runMenuWithClient(t, context.Background(), strings.NewReader("d\nw\np\n"), interrupts, client)
+
+	if !strings.Contains(out, "Stopped fetching the deaths stats") {
+		t.Errorf("Expected the fetch to be stopped, got %s", out)
+	}
+	if !strings.Contains(out, "Figures per 100k people are now on") {
+		t.Errorf("Expected the menu to carry on after the interrupt, got %s", out)
+	}
+}
+
+func TestRunMenu_InterruptingAFetchDoesNotQuitTheMenuLater(t *testing.T) {
+	// buffered like the one signal.Notify is given, so an interrupt can be waiting when the fetch ends
+	interrupts := make(chan os.Signal, 1)
+	client := &fakeClient{stalled: true, received: make(chan struct{})}
+	go func() {
+		<-client.received
+		interrupts <- os.Interrupt
+	}()
+
+	out, _ := runMenuWithClient(t, context.Background(), strings.NewReader("d\nw\np\nr\n"), interrupts, client)
+
+	stopped := strings.Index(out, "Stopped fetching the deaths stats")
+	rolling := strings.Index(out, "The 7-day rolling average is now on")
+	if stopped == -1 || rolling < stopped {
+		t.Errorf("Expected the menu to still be running after the fetch was stopped, got %s", out)
+	}
+	if len(interrupts) != 0 {
+		t.Errorf("Expected the interrupt to be used up stopping the fetch, %d are left", len(interrupts))
+	}
+}
+
+func TestRunMenu_WarnsAboutMissingFiguresOnceUnderTheChart(t *testing.T) {
+	client := &fakeClient{missingDeaths: 2}
+
+	out, _ := runMenuWithClient(t, context.Background(), strings.NewReader("d\nw\n"), make(chan os.Signal), client)
+
+	warning := "Warning: the api has no new deaths figures for England on these days: " +
+		time.Now().AddDate(0, 0, -2).Format("02/01")
+	summary := strings.Index(out, "This week")
+	if strings.Count(out, warning) != 1 || strings.Index(out, warning) < summary || summary == -1 {
+		t.Errorf("Expected one warning under the chart and summary, got %s", out)
+	}
+}